@@ -0,0 +1,96 @@
+package odiphone
+
+// EditOp is an edit operation in a phoneme alignment.
+type EditOp int
+
+const (
+	// OpMatch means the phonemes are the same.
+	OpMatch EditOp = iota
+	// OpSubstitute means a phoneme in the first word is replaced by one in the second.
+	OpSubstitute
+	// OpInsert means a phoneme in the second word is absent in the first.
+	OpInsert
+	// OpDelete means a phoneme in the first word is absent in the second.
+	OpDelete
+)
+
+// String returns the name of the operation.
+func (o EditOp) String() string {
+	switch o {
+	case OpMatch:
+		return "match"
+	case OpSubstitute:
+		return "substitute"
+	case OpInsert:
+		return "insert"
+	case OpDelete:
+		return "delete"
+	}
+	return ""
+}
+
+// AlignOp is a single step in the alignment of two words. A is the phoneme
+// from the first word (empty for OpInsert) and B is the phoneme from the
+// second word (empty for OpDelete).
+type AlignOp struct {
+	Op EditOp
+	A  string
+	B  string
+}
+
+// Align returns the minimal sequence of edit operations that turns the key2
+// phonemes of word a into those of word b, in reading order. A phoneme is
+// a glyph code along with its modifier codes, eg: BH2 in ଭ୍ରମର.
+func (od *ODIphone) Align(a, b string) []AlignOp {
+	var (
+		pa = od.phonemes(od.normalize(a))
+		pb = od.phonemes(od.normalize(b))
+		d  = make([][]int, len(pa)+1)
+	)
+
+	// Levenshtein distance matrix over the phonemes.
+	for i := range d {
+		d[i] = make([]int, len(pb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(pa); i++ {
+		for j := 1; j <= len(pb); j++ {
+			cost := 1
+			if pa[i-1] == pb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+
+	// Trace back from the end, preferring matches and substitutions.
+	var (
+		out  = make([]AlignOp, 0, len(pa)+len(pb))
+		i, j = len(pa), len(pb)
+	)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && pa[i-1] == pb[j-1] && d[i][j] == d[i-1][j-1]:
+			out = append(out, AlignOp{Op: OpMatch, A: pa[i-1], B: pb[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+1:
+			out = append(out, AlignOp{Op: OpSubstitute, A: pa[i-1], B: pb[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && d[i][j] == d[i-1][j]+1:
+			out = append(out, AlignOp{Op: OpDelete, A: pa[i-1]})
+			i--
+		default:
+			out = append(out, AlignOp{Op: OpInsert, B: pb[j-1]})
+			j--
+		}
+	}
+
+	// Reverse into reading order.
+	for l, r := 0, len(out)-1; l < r; l, r = l+1, r-1 {
+		out[l], out[r] = out[r], out[l]
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhonemes(t *testing.T) {
+	phone := New()
+	require.Equal(t, []string{"BH2", "R", "M", "R3"}, phone.phonemes("ଭ୍ରମରେ"))
+	require.Equal(t, []string{"A7", "SH"}, phone.phonemes("ଅଂଶ"))
+	require.Equal(t, []string{"B5", "D", "Y21"}, phone.phonemes("ବିଦ୍ୟା"))
+	require.Empty(t, phone.phonemes(""))
+}
+
+func TestAlign(t *testing.T) {
+	phone := New()
+
+	require.Equal(t, []AlignOp{
+		{OpMatch, "BH2", "BH2"},
+		{OpMatch, "R", "R"},
+		{OpMatch, "M", "M"},
+		{OpSubstitute, "R", "N:R"},
+	}, phone.Align("ଭ୍ରମର", "ଭ୍ରମଣ"))
+
+	require.Equal(t, []AlignOp{
+		{OpMatch, "A7", "A7"},
+		{OpMatch, "SH", "SH"},
+		{OpInsert, "", "B"},
+	}, phone.Align("ଅଂଶ", "ଅଂଶବ"))
+
+	require.Equal(t, []AlignOp{
+		{OpDelete, "A7", ""},
+		{OpMatch, "SH", "SH"},
+	}, phone.Align("ଅଂଶ", "ଶ"))
+
+	require.Empty(t, phone.Align("", ""))
+	require.Equal(t, "substitute", OpSubstitute.String())
+}
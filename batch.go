@@ -0,0 +1,194 @@
+package odiphone
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/soumendrak/odiphone/bloom"
+)
+
+// maxBatchCache is the maximum number of words memoized in a single batch.
+const maxBatchCache = 100000
+
+// batchCache memoizes the keys of words within a single batch call.
+type batchCache map[string]Keys
+
+// newBatchCache returns a new batch cache, or nil if caching is disabled.
+func (od *ODIphone) newBatchCache() batchCache {
+	if !od.opt.BatchCache {
+		return nil
+	}
+	return make(batchCache)
+}
+
+// encode encodes a word, looking it up in and adding it to the cache.
+func (c batchCache) encode(od *ODIphone, word string) Keys {
+	if c == nil {
+		return od.EncodeKeys(word)
+	}
+	if k, ok := c[word]; ok {
+		return k
+	}
+
+	k := od.EncodeKeys(word)
+	if len(c) < maxBatchCache {
+		c[word] = k
+	}
+	return k
+}
+
+// EncodeScanner encodes every token read from the scanner and passes the word
+// and its keys to emit. Tokenization is left to the scanner's split function
+// (bufio.ScanWords, bufio.ScanLines, or a custom one). Empty tokens are skipped.
+// Scanning stops at the first error returned by emit or the scanner.
+func (od *ODIphone) EncodeScanner(s *bufio.Scanner, emit func(word string, keys Keys) error) error {
+	cache := od.newBatchCache()
+	for s.Scan() {
+		word := strings.TrimSpace(s.Text())
+		if word == "" {
+			continue
+		}
+		if err := emit(word, cache.encode(od, word)); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// EncodeReader reads text from r, splits it into words the same way Tokenize
+// does, and passes each word and its keys to emit.
+func (od *ODIphone) EncodeReader(r io.Reader, emit func(word string, keys Keys) error) error {
+	s := bufio.NewScanner(r)
+	s.Split(scanWords)
+	return od.EncodeScanner(s, emit)
+}
+
+// UniqueKeys reads words from r the same way EncodeReader does, and writes
+// each distinct key of theirs at the given level to w once, one per line, in
+// the order of their appearance, eg: for building a phonetic dictionary.
+// Empty keys are skipped. The seen keys are held in memory: use
+// UniqueKeysBloom for inputs with too many distinct keys for that.
+func (od *ODIphone) UniqueKeys(r io.Reader, level MatchLevel, w io.Writer) error {
+	seen := make(map[string]bool)
+	return od.uniqueKeys(r, level, w, func(key string) bool {
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		return false
+	})
+}
+
+// UniqueKeysBloom is the same as UniqueKeys, but tracks the seen keys in the
+// Bloom filter bf in bounded memory. A false positive of the filter skips a
+// key that was not seen, so size bf for the expected number of distinct
+// keys with bloom.NewWithEstimates.
+func (od *ODIphone) UniqueKeysBloom(r io.Reader, level MatchLevel, w io.Writer, bf *bloom.BloomFilter) error {
+	return od.uniqueKeys(r, level, w, bf.TestAndAddString)
+}
+
+// uniqueKeys writes the keys of the words in r at the given level to w,
+// skipping the ones that seen reports (and records) as already seen.
+func (od *ODIphone) uniqueKeys(r io.Reader, level MatchLevel, w io.Writer, seen func(key string) bool) error {
+	if level < MatchKey0 || level > MatchKey2 {
+		return fmt.Errorf("odiphone: invalid key level %v", level)
+	}
+
+	bw := bufio.NewWriter(w)
+	err := od.EncodeReader(r, func(_ string, k Keys) error {
+		key := k.at(level)
+		if key == "" || seen(key) {
+			return nil
+		}
+		if _, err := bw.WriteString(key); err != nil {
+			return err
+		}
+		return bw.WriteByte('\n')
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// scanWords is a bufio.SplitFunc that splits words on the same boundaries
+// as Tokenize.
+func scanWords(data []byte, atEOF bool) (int, []byte, error) {
+	// Skip leading boundaries.
+	start := 0
+	for start < len(data) {
+		r, n := utf8.DecodeRune(data[start:])
+		if !isBoundary(r) {
+			break
+		}
+		start += n
+	}
+
+	for i := start; i < len(data); {
+		r, n := utf8.DecodeRune(data[i:])
+		if isBoundary(r) {
+			return i + n, data[start:i], nil
+		}
+		i += n
+	}
+
+	if atEOF && len(data) > start {
+		return len(data), data[start:], nil
+	}
+
+	// Request more data.
+	return start, nil, nil
+}
+
+// EncodeMany encodes a list of words and returns their keys in the same order.
+func (od *ODIphone) EncodeMany(words []string) []Keys {
+	var (
+		out   = make([]Keys, len(words))
+		cache = od.newBatchCache()
+	)
+	for i, w := range words {
+		out[i] = cache.encode(od, w)
+	}
+	return out
+}
+
+// EncodeManyParallel is the same as EncodeMany, but spreads the words across
+// the given number of goroutines. The output order matches the input order.
+// If workers is less than 2, the words are encoded serially. With
+// Options.BatchCache, each goroutine memoizes the words it encodes.
+func (od *ODIphone) EncodeManyParallel(words []string, workers int) []Keys {
+	if workers > len(words) {
+		workers = len(words)
+	}
+	if workers < 2 {
+		return od.EncodeMany(words)
+	}
+
+	var (
+		out = make([]Keys, len(words))
+		ch  = make(chan int, workers)
+		wg  sync.WaitGroup
+	)
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache := od.newBatchCache()
+			for i := range ch {
+				out[i] = cache.encode(od, words[i])
+			}
+		}()
+	}
+
+	for i := range words {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	return out
+}
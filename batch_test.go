@@ -0,0 +1,235 @@
+package odiphone
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/soumendrak/odiphone/bloom"
+	"github.com/stretchr/testify/require"
+)
+
+// scanCommas is a bufio.SplitFunc that splits on commas.
+func scanCommas(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, ','); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func TestEncodeScanner(t *testing.T) {
+	phone := New()
+	s := bufio.NewScanner(strings.NewReader("ଅଂଶ,ଭ୍ରମର, ଭ୍ରମରେ,,ଭ୍ରମଣ"))
+	s.Split(scanCommas)
+
+	var (
+		words []string
+		keys  []Keys
+	)
+	err := phone.EncodeScanner(s, func(word string, k Keys) error {
+		words = append(words, word)
+		keys = append(keys, k)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ଅଂଶ", "ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ"}, words)
+	require.Equal(t, []Keys{
+		{"ASH", "ASH", "A7SH"},
+		{"BHRMR", "BH2RMR", "BH2RMR"},
+		{"BHRMR", "BH2RMR3", "BH2RMR3"},
+		{"BHRMN", "BH2RMN", "BH2RMN:R"},
+	}, keys)
+
+	// An error from emit stops the scan.
+	errStop := errors.New("stop")
+	s = bufio.NewScanner(strings.NewReader("ଅଂଶ,ଭ୍ରମର"))
+	s.Split(scanCommas)
+	n := 0
+	err = phone.EncodeScanner(s, func(string, Keys) error {
+		n++
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, 1, n)
+}
+
+var batchWords = []string{"ଅଂଶ", "ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ", "ଐରାବତ", "ବହିମାନେ"}
+
+func TestEncodeMany(t *testing.T) {
+	phone := New()
+	out := phone.EncodeMany(batchWords)
+	require.Len(t, out, len(batchWords))
+	for i, w := range batchWords {
+		require.Equal(t, phone.EncodeKeys(w), out[i])
+	}
+	require.Empty(t, phone.EncodeMany(nil))
+}
+
+func TestEncodeManyParallel(t *testing.T) {
+	phone := New()
+
+	var words []string
+	for i := 0; i < 200; i++ {
+		words = append(words, batchWords...)
+	}
+	exp := phone.EncodeMany(words)
+
+	for _, od := range []*ODIphone{phone, New(WithBatchCache())} {
+		for _, n := range []int{-1, 0, 1, 2, 4, 16, len(words) + 10} {
+			require.Equal(t, exp, od.EncodeManyParallel(words, n), n)
+		}
+		require.Empty(t, od.EncodeManyParallel(nil, 4))
+	}
+}
+
+// BenchmarkEncodeManyParallel measures EncodeManyParallel over the distinct
+// words of the wordlist fixture, with and without the batch cache.
+func BenchmarkEncodeManyParallel(b *testing.B) {
+	words := loadWordlist(b)
+
+	for _, bb := range []struct {
+		name  string
+		phone *ODIphone
+	}{{"cache", New(WithBatchCache())}, {"nocache", New()}} {
+		phone := bb.phone
+		for _, n := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("%s/workers-%d", bb.name, n), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					phone.EncodeManyParallel(words, n)
+				}
+			})
+		}
+	}
+}
+
+func TestEncodeReader(t *testing.T) {
+	phone := New()
+	var words []string
+	err := phone.EncodeReader(strings.NewReader(" ଅଂଶ ଭ୍ରମର।ଭ୍ରମରେ\n\nଭ୍ରମର ଭ୍ରମଣ॥"), func(w string, k Keys) error {
+		require.Equal(t, phone.EncodeKeys(w), k)
+		words = append(words, w)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ଅଂଶ", "ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମର", "ଭ୍ରମଣ"}, words)
+	require.NoError(t, phone.EncodeReader(strings.NewReader(""), nil))
+}
+
+func TestUniqueKeys(t *testing.T) {
+	const text = "ଅଂଶ ଭ୍ରମର ଭ୍ରମରେ hello ଭ୍ରମର ଭ୍ରମଣ ଲକ୍ଷ ଲଖ"
+	phone := New()
+	for _, c := range []struct {
+		level MatchLevel
+		keys  string
+	}{
+		{MatchKey0, "ASH\nBHRMR\nBHRMN\nLKH\n"},
+		{MatchKey1, "ASH\nBH2RMR\nBH2RMR3\nBH2RMN\nLKH\n"},
+		{MatchKey2, "A7SH\nBH2RMR\nBH2RMR3\nBH2RMN:R\nLKH:S\nLKH\n"},
+	} {
+		var b bytes.Buffer
+		require.NoError(t, phone.UniqueKeys(strings.NewReader(text), c.level, &b))
+		require.Equal(t, c.keys, b.String(), c.level)
+
+		b.Reset()
+		require.NoError(t, phone.UniqueKeysBloom(strings.NewReader(text), c.level, &b, bloom.NewWithEstimates(100, 0.001)))
+		require.Equal(t, c.keys, b.String(), c.level)
+	}
+
+	require.Error(t, phone.UniqueKeys(strings.NewReader(text), MatchNone, &bytes.Buffer{}))
+}
+
+func TestBatchCache(t *testing.T) {
+	words := []string{"ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମର", "ଅଂଶ", "ଭ୍ରମର"}
+	cached := New(WithBatchCache())
+	require.Equal(t, New().EncodeMany(words), cached.EncodeMany(words))
+
+	c := cached.newBatchCache()
+	c.encode(cached, "ଭ୍ରମର")
+	c.encode(cached, "ଭ୍ରମର")
+	require.Len(t, c, 1)
+
+	// It is off by default.
+	require.Nil(t, New().newBatchCache())
+}
+
+// repeatedDoc returns a document of Zipf-like repeated words.
+func repeatedDoc() []string {
+	var words []string
+	for i := 0; i < 1000; i++ {
+		words = append(words, batchWords[i%len(batchWords)], batchWords[0], batchWords[1])
+	}
+	return words
+}
+
+func BenchmarkEncodeManyCache(b *testing.B) {
+	words := repeatedDoc()
+	for _, bb := range []struct {
+		name  string
+		phone *ODIphone
+	}{{"cache", New(WithBatchCache())}, {"nocache", New()}} {
+		phone := bb.phone
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				phone.EncodeMany(words)
+			}
+		})
+	}
+}
+
+// loadWordlist returns the words of the real Odia wordlist fixture.
+func loadWordlist(tb testing.TB) []string {
+	b, err := os.ReadFile("testdata/wordlist.txt")
+	require.NoError(tb, err)
+	return strings.Fields(string(b))
+}
+
+func TestWordlist(t *testing.T) {
+	var (
+		phone = New()
+		words = loadWordlist(t)
+	)
+	require.Greater(t, len(words), 1000)
+	for _, w := range words {
+		require.True(t, IsPlausibleOdia(w), w)
+		require.Empty(t, phone.UnmappedRunes(w), w)
+		require.NotEmpty(t, phone.EncodeKeys(w).Key0, w)
+	}
+}
+
+// BenchmarkEncodeManyWordlist measures the throughput of EncodeMany over
+// real Odia words, in bytes of input per second.
+func BenchmarkEncodeManyWordlist(b *testing.B) {
+	var (
+		words = loadWordlist(b)
+		size  int64
+	)
+	for _, w := range words {
+		size += int64(len(w))
+	}
+
+	for _, bb := range []struct {
+		name  string
+		phone *ODIphone
+	}{{"cache", New(WithBatchCache())}, {"nocache", New()}} {
+		phone := bb.phone
+		b.Run(bb.name, func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				phone.EncodeMany(words)
+			}
+			b.ReportMetric(float64(len(words)), "words/op")
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package bloom provides a Bloom filter, a compact set that answers "maybe
+// in the set" or "definitely not in the set", for fuzzy membership of
+// ODIphone keys over corpora too large to hold in memory.
+package bloom
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// BloomFilter is a Bloom filter of m bits and k hash functions. It is not
+// safe for concurrent use.
+type BloomFilter struct {
+	bits []uint64
+	m, k uint
+}
+
+// New returns a new Bloom filter of m bits (at least 1) and k hash
+// functions (at least 1).
+func New(m, k uint) *BloomFilter {
+	if m < 1 {
+		m = 1
+	}
+	if k < 1 {
+		k = 1
+	}
+	return &BloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// NewWithEstimates returns a new Bloom filter sized for n items with the
+// false positive rate fp (between 0 and 1), eg: 1e6 and 0.01 is about
+// 1.2 MB.
+func NewWithEstimates(n uint, fp float64) *BloomFilter {
+	m, k := EstimateParameters(n, fp)
+	return New(m, k)
+}
+
+// EstimateParameters returns the number of bits and hash functions of a
+// Bloom filter for n items with the false positive rate fp.
+func EstimateParameters(n uint, fp float64) (m, k uint) {
+	if n < 1 {
+		n = 1
+	}
+	if fp <= 0 || fp >= 1 {
+		fp = 0.01
+	}
+	m = uint(math.Ceil(-float64(n) * math.Log(fp) / (math.Ln2 * math.Ln2)))
+	k = uint(math.Ceil(float64(m) / float64(n) * math.Ln2))
+	return m, k
+}
+
+// Cap returns the number of bits of the filter.
+func (f *BloomFilter) Cap() uint {
+	return f.m
+}
+
+// K returns the number of hash functions of the filter.
+func (f *BloomFilter) K() uint {
+	return f.k
+}
+
+// Add adds data to the filter.
+func (f *BloomFilter) Add(data []byte) *BloomFilter {
+	h1, h2 := hashes(data)
+	for i := uint(0); i < f.k; i++ {
+		n := f.location(h1, h2, i)
+		f.bits[n/64] |= 1 << (n % 64)
+	}
+	return f
+}
+
+// AddString adds a string to the filter.
+func (f *BloomFilter) AddString(s string) *BloomFilter {
+	return f.Add([]byte(s))
+}
+
+// Test reports if data may be in the filter. If false, it is definitely
+// not in the filter.
+func (f *BloomFilter) Test(data []byte) bool {
+	h1, h2 := hashes(data)
+	for i := uint(0); i < f.k; i++ {
+		n := f.location(h1, h2, i)
+		if f.bits[n/64]&(1<<(n%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString reports if a string may be in the filter.
+func (f *BloomFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// TestAndAdd reports if data may be in the filter, and adds it.
+func (f *BloomFilter) TestAndAdd(data []byte) bool {
+	ok := f.Test(data)
+	f.Add(data)
+	return ok
+}
+
+// TestAndAddString reports if a string may be in the filter, and adds it.
+func (f *BloomFilter) TestAndAddString(s string) bool {
+	return f.TestAndAdd([]byte(s))
+}
+
+// location returns the bit of the i-th hash function by double hashing.
+func (f *BloomFilter) location(h1, h2 uint64, i uint) uint {
+	return uint((h1 + uint64(i)*h2) % uint64(f.m))
+}
+
+// hashes returns the two halves of the 64-bit FNV-1a hash of data, the
+// second one made odd so that it is never 0.
+func hashes(data []byte) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write(data)
+	s := h.Sum64()
+	return s & 0xffffffff, s>>32 | 1
+}
@@ -0,0 +1,47 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilter(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+	require.Equal(t, uint(9586), f.Cap())
+	require.Equal(t, uint(7), f.K())
+
+	require.False(t, f.TestAndAddString("BH2RMR0"))
+	require.True(t, f.TestAndAddString("BH2RMR0"))
+	for i := 1; i < 1000; i++ {
+		f.AddString(fmt.Sprintf("BH2RMR%d", i))
+	}
+
+	// Added items are always found.
+	for i := 0; i < 1000; i++ {
+		require.True(t, f.TestString(fmt.Sprintf("BH2RMR%d", i)), i)
+	}
+
+	// Other items are found at about the false positive rate.
+	fp := 0
+	for i := 0; i < 10000; i++ {
+		if f.TestString(fmt.Sprintf("GHR%d", i)) {
+			fp++
+		}
+	}
+	require.Less(t, fp, 300)
+}
+
+func TestBloomFilterBounds(t *testing.T) {
+	f := New(0, 0)
+	require.Equal(t, uint(1), f.Cap())
+	require.Equal(t, uint(1), f.K())
+	require.False(t, f.TestString("GHR"))
+	require.True(t, f.AddString("GHR").TestString("GHR"))
+
+	// Invalid estimates fall back to sane ones.
+	m, k := EstimateParameters(0, 2)
+	require.Equal(t, uint(10), m)
+	require.Equal(t, uint(7), k)
+}
@@ -0,0 +1,55 @@
+package odiphone
+
+import "hash/crc32"
+
+// checksumAlphabet are the checksum characters of keys, which are not in the
+// default codes.
+const checksumAlphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// checksum returns the checksum character of a key. The CRC-32 is computed
+// with the IEEE table rather than crc32.ChecksumIEEE, to which the key
+// escapes, so that encoding into a buffer on the stack does not allocate.
+func checksum(key []byte) byte {
+	crc := ^uint32(0)
+	for _, c := range key {
+		crc = crc32.IEEETable[byte(crc)^c] ^ crc>>8
+	}
+	return checksumAlphabet[^crc%uint32(len(checksumAlphabet))]
+}
+
+// appendChecksum appends the checksum character of a key to it. Empty keys
+// are left empty.
+func appendChecksum(key []byte) []byte {
+	if len(key) == 0 {
+		return key
+	}
+	return append(key, checksum(key))
+}
+
+// withChecksums returns the keys with their checksum characters.
+func withChecksums(k Keys) Keys {
+	return Keys{
+		Key0: string(appendChecksum([]byte(k.Key0))),
+		Key1: string(appendChecksum([]byte(k.Key1))),
+		Key2: string(appendChecksum([]byte(k.Key2))),
+	}
+}
+
+// Verify reports if a key encoded with Options.Checksum has an intact
+// checksum, ie: it was not corrupted in transit or storage. A checksum
+// catches most, but not all, corruptions.
+func Verify(key string) bool {
+	if len(key) < 2 {
+		return false
+	}
+	return checksum([]byte(key[:len(key)-1])) == key[len(key)-1]
+}
+
+// StripChecksum returns a key encoded with Options.Checksum without its
+// checksum character, eg: for computing the distance between two keys.
+func StripChecksum(key string) string {
+	if key == "" {
+		return key
+	}
+	return key[:len(key)-1]
+}
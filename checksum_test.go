@@ -0,0 +1,65 @@
+package odiphone
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksum(t *testing.T) {
+	phone := New(WithChecksum())
+	for _, w := range []string{"ଘର", "ଭ୍ରମରେ", "ଲକ୍ଷ୍ୟ", "ଆ"} {
+		k, full := phone.EncodeKeys(w), New().EncodeKeys(w)
+		for i, key := range []string{k.Key0, k.Key1, k.Key2} {
+			require.True(t, Verify(key), key)
+			require.Equal(t, full.at(MatchLevel(i+1)), StripChecksum(key))
+		}
+
+		var dst [3][]byte
+		phone.EncodeTo(&dst, w)
+		require.Equal(t, k, Keys{string(dst[0]), string(dst[1]), string(dst[2])}, w)
+	}
+	require.Equal(t, Keys{"GHRi", "GHRi", "GHRi"}, phone.EncodeKeys("ଘର"))
+
+	// Empty keys are left empty, and the sentinel has no checksum.
+	require.Equal(t, Keys{}, phone.EncodeKeys("hello"))
+	require.Equal(t, Keys{"∅", "∅", "∅"}, New(WithChecksum(), WithSentinel("∅")).EncodeKeys("hello"))
+
+	// The checksum counts toward the maximum key length.
+	key := New(WithChecksum(), WithMaxKeyLen(3)).EncodeKeys("ଘର").Key2
+	require.True(t, Verify(key))
+	require.Equal(t, "GH", StripChecksum(key))
+
+	phone = New(WithChecksum(), WithMaxKeyLen(4))
+	phone.AddException("ଲଖ", Keys{"LAKH", "LAKH", "LAKHA"})
+	for _, w := range []string{"ଘର", "ଭ୍ରମରେ", "ଲକ୍ଷ୍ୟ", "ଲଖ"} {
+		k := phone.EncodeKeys(w)
+		for _, key := range []string{k.Key0, k.Key1, k.Key2} {
+			require.True(t, Verify(key), key)
+			require.LessOrEqual(t, utf8.RuneCountInString(key), 4, key)
+		}
+	}
+	require.Equal(t, "LAK", StripChecksum(phone.EncodeKeys("ଲଖ").Key2))
+}
+
+func TestVerifyCorrupted(t *testing.T) {
+	key := New(WithChecksum()).EncodeKeys("ଭ୍ରମରେ").Key2
+	require.True(t, Verify(key))
+
+	// A changed, dropped, added, or swapped character is detected.
+	for _, c := range []string{
+		"BH2RMR4" + key[len(key)-1:],
+		"BH2RMR" + key[len(key)-1:],
+		"BH2RMR33" + key[len(key)-1:],
+		"HB2RMR3" + key[len(key)-1:],
+		key[:len(key)-1] + "a",
+	} {
+		require.NotEqual(t, key, c)
+		require.False(t, Verify(c), c)
+	}
+
+	// Keys without a checksum are not verified.
+	require.False(t, Verify(""))
+	require.False(t, Verify("B"))
+}
@@ -0,0 +1,84 @@
+// odiphone prints the ODIphone keys of Odia words given as arguments, or
+// read from stdin, one line per word. With -ndjson, it prints one JSON object
+// per word, flushing every line for use in pipes.
+//
+//	odiphone ଭ୍ରମର ଭ୍ରମରେ
+//	echo "ଭ୍ରମର ଭ୍ରମରେ" | odiphone -key 1
+//	tail -f words.txt | odiphone -ndjson
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/soumendrak/odiphone"
+)
+
+// jsonKeys is a word and its keys as a line of -ndjson.
+type jsonKeys struct {
+	Word string `json:"word"`
+	Key0 string `json:"key0"`
+	Key1 string `json:"key1"`
+	Key2 string `json:"key2"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	f := flag.NewFlagSet("odiphone", flag.ContinueOnError)
+	key := f.Int("key", -1, "print only the given key (0, 1, or 2) instead of all three")
+	ndjson := f.Bool("ndjson", false, "print a JSON object of the word and its keys per line")
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	if *key < -1 || *key > 2 {
+		return fmt.Errorf("invalid -key %d: should be 0, 1, or 2", *key)
+	}
+	if *ndjson && *key >= 0 {
+		return fmt.Errorf("-key and -ndjson cannot be used together")
+	}
+
+	var (
+		od  = odiphone.New()
+		out = bufio.NewWriter(stdout)
+		enc = json.NewEncoder(out)
+	)
+	emit := func(word string, k odiphone.Keys) error {
+		if *ndjson {
+			// The same object as EncodeJSON, but of the keys already
+			// encoded instead of encoding the word again.
+			if err := enc.Encode(jsonKeys{word, k.Key0, k.Key1, k.Key2}); err != nil {
+				return err
+			}
+			return out.Flush()
+		}
+
+		keys := []string{k.Key0, k.Key1, k.Key2}
+		if *key >= 0 {
+			keys = keys[*key : *key+1]
+		}
+		_, err := fmt.Fprintln(out, strings.Join(keys, "\t"))
+		return err
+	}
+
+	var err error
+	if f.NArg() > 0 {
+		err = od.EncodeReader(strings.NewReader(strings.Join(f.Args(), " ")), emit)
+	} else {
+		err = od.EncodeReader(stdin, emit)
+	}
+	if err != nil {
+		return err
+	}
+	return out.Flush()
+}
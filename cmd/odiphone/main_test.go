@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/soumendrak/odiphone"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args []string
+		out  string
+	}{
+		{nil, "BHRMR\tBH2RMR\tBH2RMR\nBHRMR\tBH2RMR3\tBH2RMR3\n"},
+		{[]string{"-key", "0"}, "BHRMR\nBHRMR\n"},
+		{[]string{"-key", "1"}, "BH2RMR\nBH2RMR3\n"},
+		{[]string{"-key", "2"}, "BH2RMR\nBH2RMR3\n"},
+	}
+	for _, v := range tests {
+		// Words from stdin.
+		var b bytes.Buffer
+		require.NoError(t, run(v.args, strings.NewReader("ଭ୍ରମର\nଭ୍ରମରେ\n"), &b))
+		require.Equal(t, v.out, b.String(), v.args)
+
+		// Words as arguments.
+		b.Reset()
+		require.NoError(t, run(append(v.args, "ଭ୍ରମର", "ଭ୍ରମରେ"), strings.NewReader(""), &b))
+		require.Equal(t, v.out, b.String(), v.args)
+	}
+}
+
+func TestRunInvalidKey(t *testing.T) {
+	var b bytes.Buffer
+	require.Error(t, run([]string{"-key", "3"}, strings.NewReader(""), &b))
+	require.Error(t, run([]string{"-key", "x"}, strings.NewReader(""), &b))
+}
+
+func TestRunNDJSON(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, run([]string{"-ndjson"}, strings.NewReader("ଭ୍ରମର ଭ୍ରମରେ\n\"ଘର\"\n"), &b))
+	require.Equal(t, `{"word":"ଭ୍ରମର","key0":"BHRMR","key1":"BH2RMR","key2":"BH2RMR"}
+{"word":"ଭ୍ରମରେ","key0":"BHRMR","key1":"BH2RMR3","key2":"BH2RMR3"}
+{"word":"\"ଘର\"","key0":"GHR","key1":"GHR","key2":"GHR"}
+`, b.String())
+
+	// Every line is a JSON object by itself.
+	s := bufio.NewScanner(&b)
+	for s.Scan() {
+		var v map[string]string
+		require.NoError(t, json.Unmarshal(s.Bytes(), &v))
+		require.Len(t, v, 4)
+	}
+
+	// The lines are those of EncodeJSON.
+	od := odiphone.New()
+	b.Reset()
+	require.NoError(t, run([]string{"-ndjson"}, strings.NewReader("ଭ୍ରମର <ଘର>"), &b))
+	require.Equal(t, od.EncodeJSON("ଭ୍ରମର")+"\n"+od.EncodeJSON("<ଘର>")+"\n", b.String())
+
+	require.Error(t, run([]string{"-ndjson", "-key", "0"}, strings.NewReader(""), &b))
+}
@@ -0,0 +1,116 @@
+package odiphone
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tableEntry is a glyph of one of the tables of a tokenizer.
+type tableEntry struct {
+	table, glyph, code string
+}
+
+// Validate returns an error if the tables of a tokenizer have codes that
+// collide, ie: glyphs or sequences of glyphs that are not pronounced the same
+// but encode to the same key2, eg: after SetCode, AddCompound, or with an
+// Options.VelarNasal that is the code of a sequence.
+func (od *ODIphone) Validate() error {
+	if c := od.codeCollisions(); len(c) > 0 {
+		return fmt.Errorf("odiphone: colliding codes: %s", strings.Join(c, "; "))
+	}
+	return nil
+}
+
+// codeCollisions returns the glyphs of the tables of a tokenizer that encode
+// to the same key2 as other glyphs: glyphs with the same code that are not
+// in sharedCodes or merged by the dialect, and glyphs and modifiers whose
+// code is the code of another followed by the codes of a sequence of glyphs
+// and modifiers that are not in spelledCodes (eg: a consonant coded "K3" is
+// କ with େ, one coded "TT" is ତତ, and a modifier coded "L6" is ଲ with ୁ).
+func (od *ODIphone) codeCollisions() []string {
+	var glyphs, mods []tableEntry
+	for _, t := range []struct {
+		name string
+		m    map[string]string
+	}{{"vowels", od.vowels}, {"consonants", od.consonants}, {"compounds", od.compounds}} {
+		glyphs = append(glyphs, sortedEntries(t.name, t.m)...)
+	}
+	mods = sortedEntries("modifiers", od.modifiers)
+
+	var out []string
+	for _, es := range [][]tableEntry{glyphs, mods} {
+		for i, a := range es {
+			for _, b := range es[i+1:] {
+				if a.code == b.code && !od.sharesCode(a.glyph, b.glyph) {
+					out = append(out, fmt.Sprintf("%s: %s = %s: %s = %q", a.table, a.glyph, b.table, b.glyph, a.code))
+				}
+			}
+		}
+	}
+
+	all := append(append([]tableEntry(nil), glyphs...), mods...)
+	codes := make([]string, len(all))
+	for i, e := range all {
+		codes[i] = e.code
+	}
+	for _, e := range all {
+		if s, ok := spelledCodes[e.glyph]; ok && strings.Join(od.phonemes(od.normalize(s)), "") == e.code {
+			continue
+		}
+		for _, b := range all {
+			if len(e.code) > len(b.code) && strings.HasPrefix(e.code, b.code) && concatenation(e.code[len(b.code):], codes) {
+				out = append(out, fmt.Sprintf("%s: %s = %q is %s + %q", e.table, e.glyph, e.code, b.glyph, e.code[len(b.code):]))
+			}
+		}
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// sharesCode reports if two glyphs have the same code by design.
+func (od *ODIphone) sharesCode(a, b string) bool {
+	d := dialects[od.opt.Dialect]
+	if _, ok := d[a]; ok {
+		return true
+	}
+	if _, ok := d[b]; ok {
+		return true
+	}
+	for _, s := range sharedCodes {
+		n := 0
+		for _, g := range s {
+			if g == a || g == b {
+				n++
+			}
+		}
+		if n == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// concatenation reports if s is a concatenation of codes.
+func concatenation(s string, codes []string) bool {
+	if s == "" {
+		return true
+	}
+	for _, c := range codes {
+		if c != "" && strings.HasPrefix(s, c) && concatenation(s[len(c):], codes) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedEntries returns the entries of a table sorted by glyph.
+func sortedEntries(table string, m map[string]string) []tableEntry {
+	es := make([]tableEntry, 0, len(m))
+	for g, c := range m {
+		es = append(es, tableEntry{table, g, c})
+	}
+	sort.Slice(es, func(i, j int) bool { return es[i].glyph < es[j].glyph })
+	return es
+}
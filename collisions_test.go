@@ -0,0 +1,77 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableIntegrity(t *testing.T) {
+	for _, o := range []Options{
+		DefaultOptions(),
+		{Historic: true},
+		{Dialect: DialectCoastal},
+		{Dialect: DialectWestern},
+		{SplitAspiration: true},
+		{HardSounds: true},
+		{VelarNasal: "Ṅ"},
+	} {
+		require.Empty(t, NewWithOptions(o).codeCollisions(), "%+v", o)
+	}
+
+	// A code that is another followed by modifier codes collides with it.
+	phone := New()
+	require.NoError(t, phone.SetCode("ଙ", "K3"))
+	require.NoError(t, phone.SetCode("ଙ୍ଗ", "G1"))
+	require.NoError(t, phone.SetCode("ୌ", "31"))
+	require.Equal(t, []string{
+		`compounds: ଙ୍ଗ = "G1" is ଗ + "1"`,
+		`consonants: ଙ = "K3" is କ + "3"`,
+		`modifiers: ୌ = "31" is େ + "1"`,
+		`modifiers: ୌ = "31" is ୈ + "1"`,
+		`modifiers: ୌ = "31" is ୖ + "1"`,
+		`modifiers: ୌ = "31" is ୗ + "1"`,
+	}, phone.codeCollisions())
+	require.Equal(t, phone.EncodeKeys("କେ"), phone.EncodeKeys("ଙ"))
+
+	// A code that is the concatenation of the codes of a sequence collides
+	// with it, unless it is the code of the sequence by design.
+	phone = New()
+	require.NoError(t, phone.SetCode("ଟ", "TT"))
+	require.NoError(t, phone.SetCode("ଙ୍କ", "NK"))
+	require.Equal(t, []string{
+		`compounds: ଙ୍କ = "NK" is ନ + "K"`,
+		`consonants: ଟ = "TT" is ତ + "T"`,
+	}, phone.codeCollisions())
+	require.Equal(t, phone.EncodeKeys("ତତ"), phone.EncodeKeys("ଟ"))
+	require.Equal(t, phone.EncodeKeys("ଅନକ"), phone.EncodeKeys("ଅଙ୍କ"))
+
+	phone = New()
+	for _, p := range [][2]string{
+		{"ଟ", "ତତ"}, {"ଛ", "ଚହ"}, {"ଠ", "ଟହ"}, {"ଣ", "ନହ"}, {"ଖା", "କହା"},
+		{"ଅଙ୍କ", "ଅନକ"}, {"ଅଞ୍ଜ", "ଅନଜ"}, {"ଭକ୍ତ", "ଭକତ"},
+	} {
+		require.NotEqual(t, phone.EncodeKeys(p[0]).Key2, phone.EncodeKeys(p[1]).Key2, p)
+	}
+	require.Equal(t, phone.EncodeKeys("ଅନ୍କ"), phone.EncodeKeys("ଅଙ୍କ"))
+
+	// Glyphs with the same code collide unless they share it by design,
+	// and a modifier collides with a glyph and modifiers.
+	phone = New(WithHistoric())
+	require.NoError(t, phone.SetCode("ଵ", "B:W"))
+	require.NoError(t, phone.SetCode("ଙ୍ଗ", "WN"))
+	require.NoError(t, phone.SetCode("ୢ", "L6"))
+	require.NoError(t, phone.SetCode("ୣ", "8:R"))
+	require.Equal(t, []string{
+		`consonants: ଙ = compounds: ଙ୍ଗ = "WN"`,
+		`consonants: ଵ = consonants: ୱ = "B:W"`,
+		`modifiers: ୄ = modifiers: ୣ = "8:R"`,
+		`modifiers: ୢ = "L6" is ଲ + "6"`,
+	}, phone.codeCollisions())
+	require.Equal(t, phone.EncodeKeys("କଲୁ"), phone.EncodeKeys("କୢ"))
+
+	// The codes merged by a dialect are shared.
+	phone = New(WithDialect(DialectWestern))
+	require.Equal(t, phone.EncodeKeys("ଣ"), phone.EncodeKeys("ନ"))
+	require.Empty(t, phone.codeCollisions())
+}
@@ -0,0 +1,188 @@
+package odiphone
+
+// MatchLevel is the narrowest key level at which two words match.
+type MatchLevel int
+
+const (
+	// MatchNone means the words share no key.
+	MatchNone MatchLevel = iota
+	// MatchKey0 means the words share the broad key0.
+	MatchKey0
+	// MatchKey1 means the words share key1 (and therefore key0).
+	MatchKey1
+	// MatchKey2 means the words share the narrow key2 (and therefore all keys).
+	MatchKey2
+)
+
+// String returns the name of the key level.
+func (m MatchLevel) String() string {
+	switch m {
+	case MatchKey0:
+		return "key0"
+	case MatchKey1:
+		return "key1"
+	case MatchKey2:
+		return "key2"
+	}
+	return "none"
+}
+
+// Compare encodes two words and returns the narrowest key level at which
+// they match.
+func (od *ODIphone) Compare(a, b string) MatchLevel {
+	return od.EncodeKeys(a).MatchLevel(od.EncodeKeys(b))
+}
+
+// Equal returns true if all three keys are the same.
+func (k Keys) Equal(o Keys) bool {
+	return k.Key0 == o.Key0 && k.Key1 == o.Key1 && k.Key2 == o.Key2
+}
+
+// MatchLevel returns the narrowest key level at which the keys match, so that
+// already encoded words can be compared without encoding them again.
+func (k Keys) MatchLevel(o Keys) MatchLevel {
+	switch {
+	case k.Key2 == o.Key2:
+		return MatchKey2
+	case k.Key1 == o.Key1:
+		return MatchKey1
+	case k.Key0 == o.Key0:
+		return MatchKey0
+	}
+	return MatchNone
+}
+
+// Similarity returns the phonetic similarity of two words between 0 and 1,
+// which is the fraction of key levels at which they match.
+func (od *ODIphone) Similarity(a, b string) float64 {
+	return od.SimilarityWeighted(a, b, [3]float64{1, 1, 1})
+}
+
+// SimilarityWeighted returns the phonetic similarity of two words between
+// 0 and 1, where each matching key level (key0, key1, key2) contributes its
+// weight in w to the score. For instance, {1, 2, 4} makes a key2 match count
+// more than a key0 match. Negative weights are treated as 0.
+func (od *ODIphone) SimilarityWeighted(a, b string, w [3]float64) float64 {
+	return similarity(od.EncodeKeys(a), od.EncodeKeys(b), w)
+}
+
+// SimilarityMatrix returns the Similarity of every pair of words, where
+// m[i][j] is that of words[i] and words[j], eg: for clustering words by
+// their pronunciation. Each word is encoded once. The matrix is symmetric,
+// with a diagonal of 1.
+func (od *ODIphone) SimilarityMatrix(words []string) [][]float64 {
+	var (
+		keys = od.EncodeMany(words)
+		m    = make([][]float64, len(words))
+	)
+	for i := range m {
+		m[i] = make([]float64, len(words))
+		m[i][i] = 1
+		for j := 0; j < i; j++ {
+			m[i][j] = similarity(keys[i], keys[j], [3]float64{1, 1, 1})
+			m[j][i] = m[i][j]
+		}
+	}
+	return m
+}
+
+// similarity is the SimilarityWeighted of two words by their keys.
+func similarity(ka, kb Keys, w [3]float64) float64 {
+	var (
+		match = [3]bool{ka.Key0 == kb.Key0, ka.Key1 == kb.Key1, ka.Key2 == kb.Key2}
+
+		score, total float64
+	)
+	for i, m := range match {
+		if w[i] <= 0 {
+			continue
+		}
+		total += w[i]
+		if m {
+			score += w[i]
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return score / total
+}
+
+// CollisionGroups encodes a list of words and returns the keys at the given
+// level that are shared by more than one distinct word, mapped to those words
+// in the order of their appearance. It helps judge how broadly a key level
+// buckets words in a corpus.
+func (od *ODIphone) CollisionGroups(words []string, level MatchLevel) map[string][]string {
+	var (
+		groups = make(map[string][]string)
+		seen   = make(map[string]bool)
+	)
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+
+		if k := od.EncodeKeys(w).at(level); k != "" {
+			groups[k] = append(groups[k], w)
+		}
+	}
+
+	for k, g := range groups {
+		if len(g) < 2 {
+			delete(groups, k)
+		}
+	}
+	return groups
+}
+
+// CollisionRate encodes a list of words and returns the ratio of distinct
+// words to distinct keys at each of key0, key1, and key2: 1 when no two words
+// share a key, and higher the more broadly the level buckets words. Words
+// without keys are not counted, and the ratio is 0 for a level without any.
+func (od *ODIphone) CollisionRate(words []string) [3]float64 {
+	var (
+		keys [3]map[string]bool
+		n    [3]int
+		seen = make(map[string]bool)
+	)
+	for i := range keys {
+		keys[i] = make(map[string]bool)
+	}
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+
+		k := od.EncodeKeys(w)
+		for i, key := range [3]string{k.Key0, k.Key1, k.Key2} {
+			if key != "" {
+				keys[i][key] = true
+				n[i]++
+			}
+		}
+	}
+
+	var rate [3]float64
+	for i, ks := range keys {
+		if len(ks) > 0 {
+			rate[i] = float64(n[i]) / float64(len(ks))
+		}
+	}
+	return rate
+}
+
+// at returns the key at the given level, or an empty string for MatchNone.
+func (k Keys) at(level MatchLevel) string {
+	switch level {
+	case MatchKey0:
+		return k.Key0
+	case MatchKey1:
+		return k.Key1
+	case MatchKey2:
+		return k.Key2
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		a, b  string
+		level MatchLevel
+		sim   float64
+	}{
+		{"ଭ୍ରମର", "ଭ୍ରମର", MatchKey2, 1},
+		{"ଭ୍ରମର", "ଭ୍ରମରେ", MatchKey0, 1.0 / 3},
+		{"ଭ୍ରମର", "ଭ୍ରମଣ", MatchNone, 0},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.level, phone.Compare(v.a, v.b), v.a+" "+v.b)
+		require.InDelta(t, v.sim, phone.Similarity(v.a, v.b), 1e-9, v.a+" "+v.b)
+	}
+}
+
+func TestSimilarityWeighted(t *testing.T) {
+	phone := New()
+
+	// Same words always score 1 and unrelated words 0.
+	require.Equal(t, 1.0, phone.SimilarityWeighted("ଭ୍ରମର", "ଭ୍ରମର", [3]float64{1, 2, 4}))
+	require.Equal(t, 0.0, phone.SimilarityWeighted("ଭ୍ରମର", "ଭ୍ରମଣ", [3]float64{1, 2, 4}))
+
+	// ଭ୍ରମର and ଭ୍ରମରେ only match at key0, so its weight drives the score.
+	var (
+		a, b = "ଭ୍ରମର", "ଭ୍ରମରେ"
+		low  = phone.SimilarityWeighted(a, b, [3]float64{1, 2, 4})
+		eq   = phone.SimilarityWeighted(a, b, [3]float64{1, 1, 1})
+		high = phone.SimilarityWeighted(a, b, [3]float64{4, 2, 1})
+	)
+	require.InDelta(t, 1.0/7, low, 1e-9)
+	require.InDelta(t, 1.0/3, eq, 1e-9)
+	require.InDelta(t, 4.0/7, high, 1e-9)
+	require.Equal(t, phone.Similarity(a, b), eq)
+
+	// Only key0 is weighted.
+	require.Equal(t, 1.0, phone.SimilarityWeighted(a, b, [3]float64{1, 0, 0}))
+	require.Equal(t, 0.0, phone.SimilarityWeighted(a, b, [3]float64{0, 0, 0}))
+}
+
+func TestSimilarityMatrix(t *testing.T) {
+	phone := New()
+	words := []string{"ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ", "ଲକ୍ଷ", "ଲଖ", "ଭ୍ରମର"}
+	m := phone.SimilarityMatrix(words)
+	require.Len(t, m, len(words))
+	for i := range words {
+		require.Len(t, m[i], len(words))
+		require.Equal(t, 1.0, m[i][i], words[i])
+		for j := range words {
+			require.Equal(t, m[i][j], m[j][i], "%s %s", words[i], words[j])
+			require.Equal(t, phone.Similarity(words[i], words[j]), m[i][j], "%s %s", words[i], words[j])
+		}
+	}
+	require.Equal(t, 1.0/3, m[0][1])
+	require.Equal(t, 2.0/3, m[3][4])
+	require.Equal(t, 0.0, m[0][3])
+	require.Equal(t, 1.0, m[0][5])
+
+	require.Empty(t, phone.SimilarityMatrix(nil))
+}
+
+func TestCollisionGroups(t *testing.T) {
+	phone := New()
+	words := []string{"ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ", "ଲକ୍ଷ", "ଲଖ", "ଭ୍ରମର", "ଅଂଶ"}
+
+	require.Equal(t, map[string][]string{
+		"BHRMR": {"ଭ୍ରମର", "ଭ୍ରମରେ"},
+		"LKH":   {"ଲକ୍ଷ", "ଲଖ"},
+	}, phone.CollisionGroups(words, MatchKey0))
+
+	require.Equal(t, map[string][]string{
+		"LKH": {"ଲକ୍ଷ", "ଲଖ"},
+	}, phone.CollisionGroups(words, MatchKey1))
+
+	require.Empty(t, phone.CollisionGroups(words, MatchKey2))
+	require.Empty(t, phone.CollisionGroups(words, MatchNone))
+}
+
+func TestCollisionRate(t *testing.T) {
+	phone := New()
+	words := []string{"ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ", "ଲକ୍ଷ", "ଲଖ", "ଭ୍ରମର", "ଅଂଶ", "hello"}
+
+	// 6 distinct words with keys, in 4 key0, 5 key1, and 6 key2 buckets.
+	require.Equal(t, [3]float64{1.5, 1.2, 1}, phone.CollisionRate(words))
+
+	// Repeated words are counted once, and words without keys not at all.
+	require.Equal(t, [3]float64{1, 1, 1}, phone.CollisionRate([]string{"ଘର", "ଘର", "ଭ୍ରମର"}))
+	require.Equal(t, [3]float64{}, phone.CollisionRate([]string{"hello"}))
+	require.Equal(t, [3]float64{}, phone.CollisionRate(nil))
+}
+
+func TestCompareNormalization(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		a, b string
+	}{
+		{"ଭ୍ରମର", "  ଭ୍ରମର \t"},
+		{"ଭ୍ରମର", "\nଭ୍ରମର abc"},
+		{"ଭ୍ରମର", "ABC-ଭ୍ରମର!"},
+		// Decomposed and composed vowel signs.
+		{"ଘ\u0b4bଡ଼ା", "ଘ\u0b47\u0b3eଡ଼ା"},
+		{"ଦ\u0b48ବ", "ଦ\u0b47\u0b56ବ"},
+		{"ଗ\u0b4cର", "ଗ\u0b47\u0b57ର"},
+		// Precomposed and decomposed nukta.
+		{"ବ\u0b5c", "ବ\u0b21\u0b3c"},
+	}
+	for _, v := range tests {
+		require.Equal(t, MatchKey2, phone.Compare(v.a, v.b), v.a+" "+v.b)
+		require.Equal(t, 1.0, phone.Similarity(v.a, v.b), v.a+" "+v.b)
+	}
+}
+
+func TestKeysMatchLevel(t *testing.T) {
+	var (
+		a = Keys{"BHRMR", "BH2RMR", "BH2RMR"}
+		b = Keys{"BHRMR", "BH2RMR3", "BH2RMR3"}
+		c = Keys{"BHRMR", "BH2RMR", "BH2RMR7"}
+		d = Keys{"BHRMN", "BH2RMN", "BH2RMN:R"}
+	)
+	require.True(t, a.Equal(a))
+	require.True(t, a.Equal(Keys{"BHRMR", "BH2RMR", "BH2RMR"}))
+	require.False(t, a.Equal(b))
+	require.False(t, a.Equal(c))
+
+	require.Equal(t, MatchKey2, a.MatchLevel(a))
+	require.Equal(t, MatchKey1, a.MatchLevel(c))
+	require.Equal(t, MatchKey0, a.MatchLevel(b))
+	require.Equal(t, MatchNone, a.MatchLevel(d))
+	require.Equal(t, b.MatchLevel(a), a.MatchLevel(b))
+}
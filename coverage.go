@@ -0,0 +1,79 @@
+package odiphone
+
+import (
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// CoverageReport summarizes the coverage of the Oriya unicode block by the
+// phonetic tables.
+type CoverageReport struct {
+	// Vowels, Consonants, Compounds and Modifiers are the number of entries
+	// in each table.
+	Vowels     int
+	Consonants int
+	Compounds  int
+	Modifiers  int
+
+	// Letters is the number of letters and signs of the Oriya block that
+	// are in the tables, out of TotalLetters.
+	Letters      int
+	TotalLetters int
+
+	// Unmapped are the letters and signs of the Oriya block that are not in
+	// any table, in codepoint order. Digits and symbols are not counted.
+	Unmapped []rune
+}
+
+// Coverage returns a report of the letters and signs of the Oriya block
+// that the phonetic tables map, and those that they don't, to guide work on
+// the tables.
+func Coverage() CoverageReport {
+	out := CoverageReport{
+		Vowels:     len(vowels),
+		Consonants: len(consonants),
+		Compounds:  len(compounds),
+		Modifiers:  len(modifiers),
+	}
+
+	var (
+		tables = []map[string]string{vowels, consonants, compounds, modifiers}
+		known  = make(map[rune]bool)
+	)
+	for _, m := range tables {
+		for k := range m {
+			for _, r := range k {
+				known[r] = true
+			}
+		}
+	}
+
+	// mapped reports if a rune is in a table as is, or in its NFC form as the
+	// input is normalized, eg: ଡ଼ = ଡ + ଼.
+	mapped := func(r rune) bool {
+		if known[r] {
+			return true
+		}
+		g := norm.NFC.String(string(r))
+		for _, m := range tables {
+			if _, ok := m[g]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	for r := rune(oriyaBlock); r < oriyaBlock+0x80; r++ {
+		if !unicode.Is(unicode.Oriya, r) || !(unicode.IsLetter(r) || unicode.IsMark(r)) {
+			continue
+		}
+		out.TotalLetters++
+		if mapped(r) {
+			out.Letters++
+		} else {
+			out.Unmapped = append(out.Unmapped, r)
+		}
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	c := Coverage()
+	require.Equal(t, 12, c.Vowels)
+	require.Equal(t, 36, c.Consonants)
+	require.Equal(t, 9, c.Compounds)
+	require.Equal(t, 19, c.Modifiers)
+
+	// 69 of the 74 letters and signs are mapped.
+	require.Equal(t, 74, c.TotalLetters)
+	require.Equal(t, 69, c.Letters)
+
+	// The rare vocalic l and the sign overline are unmapped, while the
+	// precomposed flaps are mapped after normalization.
+	require.Equal(t, []rune{'ଌ', '୕', 'ୡ', 'ୢ', 'ୣ'}, c.Unmapped)
+
+	// The unmapped letters are the ones the encoder drops, except for the
+	// overline, which is stripped by normalization.
+	phone := New()
+	for _, r := range c.Unmapped {
+		if r != '୕' {
+			require.Equal(t, []rune{r}, phone.UnmappedRunes("କ"+string(r)), string(r))
+		}
+	}
+	for _, w := range []string{"ଡ଼", "ଢ଼", "ୟ", "ୱ", "ୠ", "ଁ"} {
+		require.Empty(t, phone.UnmappedRunes("କ"+w), w)
+	}
+
+	// A word with an unmapped letter has only the others counted, eg: 2 of
+	// the 3 letters of ଌକାର.
+	_, n := phone.EncodeCount("ଌକାର")
+	require.Equal(t, 2, n)
+	require.Equal(t, []rune{'ଌ'}, phone.UnmappedRunes("ଌକାର"))
+}
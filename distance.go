@@ -0,0 +1,30 @@
+package odiphone
+
+import "github.com/soumendrak/odiphone/keydist"
+
+// KeyDistance returns the Levenshtein edit distance between two phonetic
+// keys, counted in runes. It is keydist.Distance.
+func KeyDistance(a, b string) int {
+	return keydist.Distance(a, b)
+}
+
+// CommonPrefixLen returns the number of leading runes that two phonetic keys
+// have in common. It is keydist.CommonPrefixLen.
+func CommonPrefixLen(a, b string) int {
+	return keydist.CommonPrefixLen(a, b)
+}
+
+// KeyTrigrams returns the unique, padded trigrams of a phonetic key like
+// PostgreSQL's pg_trgm does. It is keydist.Trigrams.
+func KeyTrigrams(key string) []string {
+	return keydist.Trigrams(key)
+}
+
+func minInt(a int, b ...int) int {
+	for _, v := range b {
+		if v < a {
+			a = v
+		}
+	}
+	return a
+}
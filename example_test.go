@@ -0,0 +1,38 @@
+package odiphone_test
+
+import (
+	"fmt"
+
+	"github.com/soumendrak/odiphone"
+)
+
+func ExampleODIphone_Encode() {
+	od := odiphone.New()
+	fmt.Println(od.Encode("ଭ୍ରମର"))
+	// Output: BHRMR BH2RMR BH2RMR
+}
+
+func ExampleODIphone_EncodeKeys() {
+	od := odiphone.New()
+	k := od.EncodeKeys("ଅଂଶ")
+	fmt.Println(k.Key0, k.Key1, k.Key2)
+	// Output: ASH ASH A7SH
+}
+
+func ExampleODIphone_Compare() {
+	od := odiphone.New()
+	fmt.Println(od.Compare("ଭ୍ରମର", "ଭ୍ରମରେ"))
+	fmt.Println(od.Compare("ଭ୍ରମର", "ଭ୍ରମଣ"))
+	// Output:
+	// key0
+	// none
+}
+
+func ExampleODIphone_Similarity() {
+	od := odiphone.New()
+	fmt.Printf("%.2f\n", od.Similarity("ଭ୍ରମର", "ଭ୍ରମର"))
+	fmt.Printf("%.2f\n", od.Similarity("ଭ୍ରମର", "ଭ୍ରମରେ"))
+	// Output:
+	// 1.00
+	// 0.33
+}
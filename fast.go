@@ -0,0 +1,78 @@
+package odiphone
+
+import "strings"
+
+// oriyaBlock is the first codepoint of the Oriya unicode block.
+const oriyaBlock = 0x0B00
+
+// simpleGlyph is a glyph that is encoded by the fast path.
+type simpleGlyph struct {
+	code string
+
+	// vowel is set for independent vowels, which are only simple at the
+	// start of a word as they take a glide after an i or u vowel elsewhere.
+	vowel bool
+
+	// modifier is set for modifiers, which are part of the phoneme of the
+	// glyph before them.
+	modifier bool
+}
+
+// compileSimple builds the table of simple glyphs: the vowels, consonants,
+// and modifiers that encode to their own code regardless of their neighbours.
+// The halant and the nukta are not simple as they form compounds,
+// conjuncts, and ya-phalas with their neighbours.
+func (od *ODIphone) compileSimple() {
+	od.simple = [0x80]simpleGlyph{}
+	for i, m := range []map[string]string{od.vowels, od.consonants, od.modifiers} {
+		for k, v := range m {
+			r := []rune(k)
+			if len(r) != 1 || r[0] < oriyaBlock || r[0] >= oriyaBlock+0x80 || r[0] == halant || r[0] == nukta {
+				continue
+			}
+			od.simple[r[0]-oriyaBlock] = simpleGlyph{code: v, vowel: i == 0, modifier: i == 2}
+		}
+	}
+
+	// Compounds are made with the halant or the nukta, but if one is not,
+	// its glyphs are not simple either.
+	for k := range od.compounds {
+		if strings.ContainsRune(k, halant) || strings.ContainsRune(k, nukta) {
+			continue
+		}
+		for _, r := range k {
+			if r >= oriyaBlock && r < oriyaBlock+0x80 {
+				od.simple[r-oriyaBlock] = simpleGlyph{}
+			}
+		}
+	}
+}
+
+// appendSimpleKey appends key2 of a normalized input that only has simple
+// glyphs (ie: consonants and vowels with plain vowel signs and modifiers,
+// without conjuncts or glides) to dst by concatenating their codes, and the
+// offsets in dst at which its phonemes start to bs, which is what the full
+// pipeline does for such words, but much faster. It returns dst and bs as
+// they were and false if the input is not simple and has to be processed by
+// the pipeline.
+func (od *ODIphone) appendSimpleKey(dst []byte, bs []int, input string) ([]byte, []int, bool) {
+	if od.opt.InherentVowel {
+		return dst, bs, false
+	}
+
+	n, m := len(dst), len(bs)
+	for i, r := range input {
+		if r < oriyaBlock || r >= oriyaBlock+0x80 {
+			return dst[:n], bs[:m], false
+		}
+		g := od.simple[r-oriyaBlock]
+		if g.code == "" || (g.vowel && i > 0) {
+			return dst[:n], bs[:m], false
+		}
+		if !g.modifier {
+			bs = append(bs, len(dst))
+		}
+		dst = append(dst, g.code...)
+	}
+	return dst, bs, true
+}
@@ -0,0 +1,66 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimpleKey(t *testing.T) {
+	od := New()
+	for _, w := range []string{"ଘର", "ଘରେ", "କଥା", "ଅଂଶ", "ଆମେ", "ଶାଳ", "ବଂଶୀ", "ଚାଁଦ", "ୱାଟ", ""} {
+		_, _, ok := od.appendSimpleKey(nil, nil, od.normalize(w))
+		require.True(t, ok, w)
+	}
+	for _, w := range []string{"ଭ୍ରମର", "ବଡ଼", "ଲକ୍ଷ", "ଦିଆ", "ନୂଆ", "ବିଦ୍ୟା", "ଚିହ୍ନ", "ନଈ", "ଭ୍ରମ୰ର"} {
+		key2, bs, ok := od.appendSimpleKey([]byte("K"), []int{0}, od.normalize(w))
+		require.False(t, ok, w)
+		require.Equal(t, "K", string(key2), w)
+		require.Equal(t, []int{0}, bs, w)
+	}
+
+	// Not with the inherent vowel, which is marked by the pipeline.
+	_, _, ok := New(WithInherentVowel()).appendSimpleKey(nil, nil, "ଘର")
+	require.False(t, ok)
+}
+
+func TestSimpleKeyPipeline(t *testing.T) {
+	words := []string{"ଘର", "ଘରେ", "କଥା", "ଅଂଶ", "ଆମେ", "ଶାଳ", "ବଂଶୀ", "ଚାଁଦ", "ୱାଟ", "ଖଣ", "ଙକା", "ଐରାବତ", "ଔଷଧ"}
+	for _, opts := range [][]Option{
+		nil,
+		{WithDialect(DialectWestern)},
+		{WithSplitAspiration(), WithVelarNasal("Ṅ")},
+	} {
+		od := New(opts...)
+		for _, w := range words {
+			w = od.normalize(w)
+			key2, bs, ok := od.appendSimpleKey(nil, nil, w)
+			require.True(t, ok, w)
+			want, wantBs := od.appendProcessed(nil, nil, w)
+			require.Equal(t, string(want), string(key2), w)
+			require.Equal(t, wantBs, bs, w)
+		}
+	}
+}
+
+// simpleWords are short words without conjuncts, the common case of the
+// fast path.
+var simpleWords = []string{"ଘର", "କଥା", "ଆମେ", "ଶାଳ", "ବଂଶୀ", "ଚାଁଦ", "ପାଣି"}
+
+func BenchmarkEncodeSimple(b *testing.B) {
+	od := New()
+	b.Run("fast", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			od.EncodeKeys(simpleWords[i%len(simpleWords)])
+		}
+	})
+	b.Run("pipeline", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			in := od.normalize(simpleWords[i%len(simpleWords)])
+			key2, bs := od.appendProcessed(nil, nil, in)
+			_, _ = appendKey(nil, key2, bs, '7', 0, -1), appendKey(nil, key2, bs, '0', 0, -1)
+		}
+	})
+}
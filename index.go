@@ -0,0 +1,170 @@
+package odiphone
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/soumendrak/odiphone/bloom"
+)
+
+// ESTokens returns the deduplicated key0 and key1 of a word as tokens for a
+// keyword field in a search engine such as Elasticsearch or Lucene. Index the
+// tokens of every word in a multi-valued keyword field at ingest time, and
+// query the field with the tokens of the search term.
+func (od *ODIphone) ESTokens(word string) []string {
+	k := od.EncodeKeys(word)
+	switch {
+	case k.Key0 == "":
+		return nil
+	case k.Key0 == k.Key1:
+		return []string{k.Key0}
+	}
+	return []string{k.Key0, k.Key1}
+}
+
+// CanonicalKey returns a single deterministic key of a word for bucketed
+// storage and sorted indexes: key1, then key2 as a tie-break, separated by a
+// space, eg: ଭ୍ରମରେ = "BH2RMR3 BH2RMR3". This is the recommended index key.
+// Words with the same key1 share a prefix, and as the space sorts before all
+// the codes, sorting canonical keys sorts words by key1 and then by key2.
+// A key that is empty (eg: truncated to nothing by Options.MaxKeyLen) falls
+// back to the next broader one, eg: ଟ = "T T" with a MaxKeyLen of 2. It is
+// Options.Sentinel for a word without any keys, such as one without Odia
+// characters.
+func (od *ODIphone) CanonicalKey(word string) string {
+	input := od.normalize(word)
+	if input == "" {
+		return od.opt.Sentinel
+	}
+
+	k := od.encodeNormalized(input)
+	key1, key2 := k.Key1, k.Key2
+	if key1 == "" {
+		key1 = k.Key0
+	}
+	if key2 == "" {
+		key2 = key1
+	}
+	if key2 == "" {
+		return od.opt.Sentinel
+	}
+	return key1 + " " + key2
+}
+
+// EncodeCombined returns the three keys of a word as a single string for
+// stores of one string per word: key0, key1, and key2 separated by sep, eg:
+// ଭ୍ରମରେ and "|" = "BHRMR|BH2RMR3|BH2RMR3". A separator or backslash in a
+// key is escaped with a backslash so that ParseCombined recovers the keys,
+// so sep must not have a backslash itself. An empty sep is "|". A word
+// without keys is just the two separators.
+func (od *ODIphone) EncodeCombined(word, sep string) string {
+	if sep == "" {
+		sep = "|"
+	}
+	k := od.EncodeKeys(word)
+	return escapeKey(k.Key0, sep) + sep + escapeKey(k.Key1, sep) + sep + escapeKey(k.Key2, sep)
+}
+
+// ParseCombined splits a string returned by EncodeCombined with the same
+// separator into the keys.
+func ParseCombined(s, sep string) (Keys, error) {
+	if sep == "" {
+		sep = "|"
+	}
+
+	var (
+		keys []string
+		b    strings.Builder
+	)
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && strings.HasPrefix(s[i+1:], sep):
+			b.WriteString(sep)
+			i += 1 + len(sep)
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '\\':
+			b.WriteByte('\\')
+			i += 2
+		case strings.HasPrefix(s[i:], sep):
+			keys = append(keys, b.String())
+			b.Reset()
+			i += len(sep)
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	keys = append(keys, b.String())
+
+	if len(keys) != 3 {
+		return Keys{}, fmt.Errorf("odiphone: %q has %d keys and not 3", s, len(keys))
+	}
+	return Keys{Key0: keys[0], Key1: keys[1], Key2: keys[2]}, nil
+}
+
+// escapeKey escapes the backslashes and separators in a key.
+func escapeKey(key, sep string) string {
+	if !strings.Contains(key, sep) && !strings.Contains(key, `\`) {
+		return key
+	}
+	key = strings.ReplaceAll(key, `\`, `\\`)
+	return strings.ReplaceAll(key, sep, `\`+sep)
+}
+
+// QueryRange returns the inclusive bounds of the keys to look up in a sorted
+// index of keys at the given level for a word, eg: with sort.SearchStrings
+// on a sorted key file, for O(log n) lookups. As words match on equal keys,
+// lo and hi are both the key of the word. They are empty for MatchNone and
+// for a word without keys.
+func (od *ODIphone) QueryRange(word string, level MatchLevel) (lo, hi string) {
+	k := od.EncodeKeys(word).at(level)
+	return k, k
+}
+
+// Index appends docID to the bucket of the word's key at the given level in
+// dst, an inverted index from keys to the IDs of the documents that have
+// words with the key. A word without keys, or MatchNone, is not indexed. An
+// ID is not appended again if it is already the last in the bucket, so that
+// documents can be indexed word by word without duplicate IDs.
+func (od *ODIphone) Index(word string, docID int, dst map[string][]int, level MatchLevel) {
+	k := od.EncodeKeys(word).at(level)
+	if k == "" {
+		return
+	}
+	if b := dst[k]; len(b) > 0 && b[len(b)-1] == docID {
+		return
+	}
+	dst[k] = append(dst[k], docID)
+}
+
+// AddToBloom adds the word's key at the given level to the Bloom filter bf,
+// for a compact fuzzy membership test over a large corpus with MightContain.
+// A word without keys, or MatchNone, is not added. Keep one filter per
+// level, as the keys of the levels are not told apart in a filter.
+func (od *ODIphone) AddToBloom(word string, bf *bloom.BloomFilter, level MatchLevel) {
+	if k := od.EncodeKeys(word).at(level); k != "" {
+		bf.AddString(k)
+	}
+}
+
+// MightContain reports if a word that sounds like the given one at the given
+// level may have been added to the Bloom filter bf with AddToBloom. It is
+// never false for such a word, but may be true for others with the false
+// positive rate of bf. It is false for a word without keys, and MatchNone.
+func (od *ODIphone) MightContain(word string, bf *bloom.BloomFilter, level MatchLevel) bool {
+	k := od.EncodeKeys(word).at(level)
+	return k != "" && bf.TestString(k)
+}
+
+// BestKey returns the most specific non-empty key of a word, ie: key2,
+// falling back to key1 and then key0 where the narrower keys are empty (eg:
+// truncated to nothing by Options.MaxKeyLen), for an index of a single key
+// per word. It is empty for a word without keys.
+func (od *ODIphone) BestKey(word string) string {
+	k := od.EncodeKeys(word)
+	for _, key := range []string{k.Key2, k.Key1} {
+		if key != "" {
+			return key
+		}
+	}
+	return k.Key0
+}
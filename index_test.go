@@ -0,0 +1,207 @@
+package odiphone
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/soumendrak/odiphone/bloom"
+	"github.com/stretchr/testify/require"
+)
+
+func TestESTokens(t *testing.T) {
+	phone := New()
+	require.Equal(t, []string{"BHRMR", "BH2RMR3"}, phone.ESTokens("ଭ୍ରମରେ"))
+	require.Equal(t, []string{"ASH"}, phone.ESTokens("ଅଂଶ"))
+	require.Empty(t, phone.ESTokens("hello"))
+}
+
+func TestCanonicalKey(t *testing.T) {
+	phone := New()
+	require.Equal(t, "BH2RMR3 BH2RMR3", phone.CanonicalKey("ଭ୍ରମରେ"))
+	require.Empty(t, phone.CanonicalKey("hello"))
+
+	// A word without Odia characters is a single sentinel.
+	require.Equal(t, "∅", New(WithSentinel("∅")).CanonicalKey("hello"))
+	require.Equal(t, "BH2RMR3 BH2RMR3", New(WithSentinel("∅")).CanonicalKey("ଭ୍ରମରେ"))
+
+	// A key truncated to nothing falls back to the next broader one, and a
+	// word without any keys is the sentinel.
+	require.Equal(t, Keys{"T", "T", ""}, New(WithMaxKeyLen(2)).EncodeKeys("ଟ"))
+	require.Equal(t, "T T", New(WithMaxKeyLen(2)).CanonicalKey("ଟ"))
+	require.Equal(t, Keys{"A", "A", ""}, New(WithMaxKeyLen(1)).EncodeKeys("ଅଂ"))
+	require.Equal(t, "A A", New(WithMaxKeyLen(1)).CanonicalKey("ଅଂ"))
+	require.Equal(t, "∅", New(WithMaxKeyLen(1), WithSentinel("∅")).CanonicalKey("କ୍ଷ"))
+	require.Empty(t, New(WithMaxKeyLen(1)).CanonicalKey("କ୍ଷ"))
+
+	words := []string{"ଭ୍ରମରେ", "ଭ୍ରମର", "ଅଂଶ", "ଲକ୍ଷ", "ଲଖ", "କୃଷ୍ଣ", "କୁଷ୍ଣ", "ଘର"}
+
+	// Stable across calls and encoders.
+	for _, w := range words {
+		require.Equal(t, phone.CanonicalKey(w), New().CanonicalKey(w), w)
+	}
+
+	// Sorting canonical keys sorts by key1, and then by key2.
+	keys := make([]string, len(words))
+	for i, w := range words {
+		keys[i] = phone.CanonicalKey(w)
+	}
+	sort.Strings(keys)
+	require.Equal(t, []string{
+		"ASH A7SH",
+		"BH2RMR BH2RMR",
+		"BH2RMR3 BH2RMR3",
+		"GHR GHR",
+		"K6SH2N K6:RSH2N:R",
+		"K6SH2N K6SH2N:R",
+		"LKH LKH",
+		"LKH LKH:S",
+	}, keys)
+}
+
+func TestEncodeCombined(t *testing.T) {
+	phone := New()
+	require.Equal(t, "BHRMR|BH2RMR3|BH2RMR3", phone.EncodeCombined("ଭ୍ରମରେ", "|"))
+	require.Equal(t, "BHRMR|BH2RMR3|BH2RMR3", phone.EncodeCombined("ଭ୍ରମରେ", ""))
+	require.Equal(t, "ASH\tASH\tA7SH", phone.EncodeCombined("ଅଂଶ", "\t"))
+
+	// A word without keys.
+	require.Equal(t, "||", phone.EncodeCombined("hello", "|"))
+	k, err := ParseCombined("||", "|")
+	require.NoError(t, err)
+	require.Equal(t, Keys{}, k)
+
+	// Separators and backslashes in keys are escaped.
+	phone.AddException("ଘର", Keys{"G|R", `G\R`, `G\|R|`})
+	s := phone.EncodeCombined("ଘର", "|")
+	require.Equal(t, `G\|R|G\\R|G\\\|R\|`, s)
+	k, err = ParseCombined(s, "|")
+	require.NoError(t, err)
+	require.Equal(t, Keys{"G|R", `G\R`, `G\|R|`}, k)
+
+	// The keys are recovered with any separator.
+	for _, sep := range []string{"|", "::", " ", "7"} {
+		for _, w := range []string{"ଭ୍ରମରେ", "ଅଂଶ", "ଘର", "hello"} {
+			k, err := ParseCombined(phone.EncodeCombined(w, sep), sep)
+			require.NoError(t, err)
+			require.Equal(t, phone.EncodeKeys(w), k, w+sep)
+		}
+	}
+
+	_, err = ParseCombined("A|B", "|")
+	require.Error(t, err)
+	_, err = ParseCombined("A|B|C|D", "|")
+	require.Error(t, err)
+}
+
+func TestQueryRange(t *testing.T) {
+	phone := New()
+	for _, c := range []struct {
+		level MatchLevel
+		key   string
+	}{
+		{MatchKey0, "BHRMR"},
+		{MatchKey1, "BH2RMR3"},
+		{MatchKey2, "BH2RMR3"},
+		{MatchNone, ""},
+	} {
+		lo, hi := phone.QueryRange("ଭ୍ରମରେ", c.level)
+		require.Equal(t, c.key, lo, c.level)
+		require.Equal(t, c.key, hi, c.level)
+	}
+
+	// Binary search of a sorted key0 index.
+	words := []string{"ଭ୍ରମରେ", "ଅଂଶ", "ଭ୍ରମର", "ଘର", "ଭ୍ରମଣ"}
+	index := make([]string, len(words))
+	for i, w := range words {
+		index[i] = phone.EncodeKeys(w).Key0
+	}
+	sort.Strings(index)
+
+	lo, hi := phone.QueryRange("ଭ୍ରମର", MatchKey0)
+	i := sort.SearchStrings(index, lo)
+	j := sort.Search(len(index), func(n int) bool { return index[n] > hi })
+	require.Equal(t, []string{"BHRMR", "BHRMR"}, index[i:j])
+}
+
+func TestIndex(t *testing.T) {
+	phone := New()
+	docs := []string{
+		"ଭ୍ରମର ଭ୍ରମରେ",
+		"ଅଂଶ ଭ୍ରମଣ ଅଂଶ",
+		"ଭ୍ରମର hello",
+	}
+
+	idx := make(map[string][]int)
+	for id, d := range docs {
+		for _, w := range Tokenize(d) {
+			phone.Index(w, id, idx, MatchKey0)
+		}
+	}
+	require.Equal(t, map[string][]int{
+		"BHRMR": {0, 2},
+		"ASH":   {1},
+		"BHRMN": {1},
+	}, idx)
+
+	idx = make(map[string][]int)
+	for id, d := range docs {
+		for _, w := range Tokenize(d) {
+			phone.Index(w, id, idx, MatchKey1)
+		}
+	}
+	require.Equal(t, []int{0, 2}, idx["BH2RMR"])
+	require.Equal(t, []int{0}, idx["BH2RMR3"])
+
+	phone.Index("ଘର", 0, idx, MatchNone)
+	require.NotContains(t, idx, "")
+	require.NotContains(t, idx, "GHR")
+}
+
+func TestBloom(t *testing.T) {
+	phone := New()
+	for _, level := range []MatchLevel{MatchKey0, MatchKey1} {
+		bf := bloom.NewWithEstimates(100, 0.001)
+		for _, w := range dict {
+			phone.AddToBloom(w, bf, level)
+		}
+		for _, w := range dict[:6] {
+			require.True(t, phone.MightContain(w, bf, level), w)
+		}
+		require.False(t, phone.MightContain("ଘର", bf, level))
+		require.False(t, phone.MightContain("hello", bf, level))
+	}
+
+	// Words that sound like the added ones test positive at the broader keys.
+	bf := bloom.NewWithEstimates(100, 0.001)
+	phone.AddToBloom("ଭ୍ରମର", bf, MatchKey0)
+	require.True(t, phone.MightContain("ଭ୍ରମରେ", bf, MatchKey0))
+	require.True(t, phone.MightContain("ଭ୍ରମର", bf, MatchKey0))
+	require.False(t, phone.MightContain("ଭ୍ରମଣ", bf, MatchKey0))
+
+	bf = bloom.NewWithEstimates(100, 0.001)
+	phone.AddToBloom("ଲଖ", bf, MatchKey1)
+	require.True(t, phone.MightContain("ଲକ୍ଷ", bf, MatchKey1))
+	require.False(t, phone.MightContain("ଲକ୍ଷ", bf, MatchKey2))
+
+	// MatchNone adds and matches nothing.
+	bf = bloom.NewWithEstimates(100, 0.001)
+	phone.AddToBloom("ଭ୍ରମର", bf, MatchNone)
+	require.False(t, phone.MightContain("ଭ୍ରମର", bf, MatchNone))
+	require.False(t, phone.MightContain("ଭ୍ରମର", bf, MatchKey0))
+}
+
+func TestBestKey(t *testing.T) {
+	phone := New()
+	require.Equal(t, "A7SH", phone.BestKey("ଅଂଶ"))
+	require.Equal(t, "BH2RMR3", phone.BestKey("ଭ୍ରମରେ"))
+	require.Empty(t, phone.BestKey("hello"))
+
+	// The narrower keys of ଛାତ are truncated to nothing, as its first
+	// phoneme CHH1 is longer than 3.
+	trunc := New(WithMaxKeyLen(3))
+	require.Equal(t, Keys{"CHH", "", ""}, trunc.EncodeKeys("ଛାତ"))
+	require.Equal(t, "CHH", trunc.BestKey("ଛାତ"))
+
+	phone.AddException("ଭ୍ରମର", Keys{"BHRMR", "BH2RMR", ""})
+	require.Equal(t, "BH2RMR", phone.BestKey("ଭ୍ରମର"))
+}
@@ -0,0 +1,207 @@
+// gen validates the phonetic tables of odiphone and writes a sorted,
+// deterministic dump of them. It fails on ambiguous table entries.
+//
+// It is run from the package directory with go generate.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tableNames are the names of the tables in the order they are dumped.
+var tableNames = []string{"vowels", "consonants", "compounds", "modifiers", "historicVowels", "historicModifiers"}
+
+// tables are the phonetic tables by name.
+type tables map[string]map[string]string
+
+func main() {
+	var (
+		src = flag.String("src", "odiphone.go", "Go file with the phonetic tables")
+		out = flag.String("o", "tables.txt", "file to write the table dump to")
+	)
+	flag.Parse()
+
+	t, err := parseTables(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := validate(t); err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := dump(t, f); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseTables parses the map literals of the phonetic tables in a Go file.
+func parseTables(path string) (tables, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	t := make(tables)
+	for _, d := range f.Decls {
+		g, ok := d.(*ast.GenDecl)
+		if !ok || g.Tok != token.VAR {
+			continue
+		}
+		for _, s := range g.Specs {
+			v := s.(*ast.ValueSpec)
+			for i, n := range v.Names {
+				if !isTable(n.Name) || i >= len(v.Values) {
+					continue
+				}
+				m, err := parseMap(v.Values[i])
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", n.Name, err)
+				}
+				t[n.Name] = m
+			}
+		}
+	}
+
+	for _, n := range tableNames {
+		if _, ok := t[n]; !ok {
+			return nil, fmt.Errorf("table %s not found in %s", n, path)
+		}
+	}
+	return t, nil
+}
+
+func isTable(name string) bool {
+	for _, n := range tableNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMap parses a map[string]string literal.
+func parseMap(e ast.Expr) (map[string]string, error) {
+	c, ok := e.(*ast.CompositeLit)
+	if !ok {
+		return nil, errors.New("not a map literal")
+	}
+
+	m := make(map[string]string)
+	for _, el := range c.Elts {
+		kv, ok := el.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, errors.New("not a key-value entry")
+		}
+		k, err := unquote(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := unquote(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func unquote(e ast.Expr) (string, error) {
+	b, ok := e.(*ast.BasicLit)
+	if !ok || b.Kind != token.STRING {
+		return "", errors.New("not a string literal")
+	}
+	return strconv.Unquote(b.Value)
+}
+
+// validate checks the tables for ambiguous entries: glyphs that are in more
+// than one table, compounds made of unknown glyphs, vowels, consonants and
+// modifiers that are not single runes of the Oriya block (as the encoder
+// looks them up by rune), and empty codes or codes with the grouping
+// markers. Compounds that contain other compounds are
+// valid as the longest compound is matched.
+func validate(t tables) error {
+	var errs []string
+
+	owner := make(map[string]string)
+	for _, n := range tableNames {
+		for _, g := range sortedKeys(t[n]) {
+			if o, ok := owner[g]; ok {
+				errs = append(errs, fmt.Sprintf("%q is in both %s and %s", g, o, n))
+			}
+			owner[g] = n
+
+			if r := []rune(g); n != "compounds" && (len(r) != 1 || r[0] < 0x0b00 || r[0] > 0x0b7f) {
+				errs = append(errs, fmt.Sprintf("%s: %q is not a single Oriya rune", n, g))
+			}
+
+			c := t[n][g]
+			if c == "" || strings.ContainsAny(c, "{}") {
+				errs = append(errs, fmt.Sprintf("%s: %q has an invalid code %q", n, g, c))
+			}
+		}
+	}
+
+	for _, a := range sortedKeys(t["compounds"]) {
+		for _, r := range a {
+			if _, ok := t["consonants"][string(r)]; ok {
+				continue
+			}
+			if _, ok := t["modifiers"][string(r)]; ok {
+				continue
+			}
+			errs = append(errs, fmt.Sprintf("compounds: %q has %q that is not a consonant or modifier", a, r))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New("ambiguous tables:\n" + strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// dump writes the tables as sorted, tab separated lines of the table name,
+// glyph, its codepoints, and its code.
+func dump(t tables, w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "# Code generated by internal/gen. DO NOT EDIT."); err != nil {
+		return err
+	}
+	for _, n := range tableNames {
+		for _, g := range sortedKeys(t[n]) {
+			var cp []string
+			for _, r := range g {
+				cp = append(cp, fmt.Sprintf("%U", r))
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", n, g, strings.Join(cp, " "), t[n][g]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTables(t *testing.T) {
+	tb, err := parseTables("../../odiphone.go")
+	require.NoError(t, err)
+	require.NoError(t, validate(tb))
+
+	// The committed dump is up to date.
+	var b bytes.Buffer
+	require.NoError(t, dump(tb, &b))
+	exp, err := os.ReadFile("../../tables.txt")
+	require.NoError(t, err)
+	require.Equal(t, string(exp), b.String(), "tables.txt is stale: run go generate")
+}
+
+func TestValidateAmbiguous(t *testing.T) {
+	tb := tables{
+		"vowels":     {"ଅ": "A"},
+		"consonants": {"କ": "K", "ଷ": "SH", "ଅ": "A"},
+		"compounds":  {"କ୍ଷ": "KSH", "କ୍ଷ୍": "KSH2", "କ୍ଅ": "{K}"},
+		"modifiers":  {"୍": "2", "ଂଁ": "7"},
+	}
+	err := validate(tb)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"ଅ" is in both vowels and consonants`)
+	// Compounds that contain others are not ambiguous as the longest wins.
+	require.NotContains(t, err.Error(), "କ୍ଷ୍")
+	require.Contains(t, err.Error(), `"କ୍ଅ" has an invalid code "{K}"`)
+	require.Contains(t, err.Error(), `modifiers: "ଂଁ" is not a single Oriya rune`)
+}
@@ -0,0 +1,183 @@
+// variants reports where the phonetic tables of another Odia encoder (eg:
+// the odphone package being consolidated into odiphone) disagree with the
+// tables of odiphone, and the words of a word list whose keys differ by
+// them. Both tables are read as dumps in the format of tables.txt.
+//
+// It is run from the package directory, eg:
+//
+//	go run ./internal/variants -other internal/variants/testdata/odphone.txt < testdata/wordlist.txt
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/soumendrak/odiphone"
+)
+
+// historicTables are the tables that odiphone only maps with
+// Options.Historic, and that the comparison skips.
+var historicTables = map[string]bool{"historicVowels": true, "historicModifiers": true}
+
+// entry is a line of a table dump.
+type entry struct {
+	table, glyph, code string
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	f := flag.NewFlagSet("variants", flag.ContinueOnError)
+	var (
+		ours  = f.String("tables", "tables.txt", "dump of the odiphone tables")
+		other = f.String("other", "", "dump of the tables of the other encoder")
+	)
+	if err := f.Parse(args); err != nil {
+		return err
+	}
+	if *other == "" {
+		return errors.New("-other is required")
+	}
+
+	a, err := readDumpFile(*ours)
+	if err != nil {
+		return err
+	}
+	b, err := readDumpFile(*other)
+	if err != nil {
+		return err
+	}
+
+	od := odiphone.New()
+	v, diffs, err := variant(od, a, b)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(stdout)
+	for _, d := range diffs {
+		fmt.Fprintln(out, d)
+	}
+	s := bufio.NewScanner(stdin)
+	s.Split(bufio.ScanWords)
+	for s.Scan() {
+		w := s.Text()
+		if ka, kb := od.EncodeKeys(w), v.EncodeKeys(w); !ka.Equal(kb) {
+			fmt.Fprintf(out, "%s\t%s %s %s\t%s %s %s\n", w, ka.Key0, ka.Key1, ka.Key2, kb.Key0, kb.Key1, kb.Key2)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+// variant returns a copy of od with the codes of the other tables, and the
+// entries that differ from ours, sorted. An entry that is not in ours is
+// added if it is a compound, and is otherwise reported as not applied. An
+// entry that is only in ours keeps its code in the copy, and is reported if
+// the other dump is a full one.
+func variant(od *odiphone.ODIphone, ours, other []entry) (*odiphone.ODIphone, []string, error) {
+	codes := make(map[string]string)
+	for _, e := range ours {
+		if !historicTables[e.table] {
+			codes[e.glyph] = e.code
+		}
+	}
+
+	var (
+		v     = od.Clone()
+		diffs []string
+		seen  = make(map[string]bool)
+	)
+	for _, e := range other {
+		if historicTables[e.table] {
+			continue
+		}
+		seen[e.glyph] = true
+		code, ok := codes[e.glyph]
+		switch {
+		case ok && code == e.code:
+			continue
+		case ok:
+			diffs = append(diffs, fmt.Sprintf("%s: %s = %s, other %s", e.table, e.glyph, code, e.code))
+			if err := v.SetCode(e.glyph, e.code); err != nil {
+				return nil, nil, err
+			}
+		case e.table == "compounds":
+			diffs = append(diffs, fmt.Sprintf("%s: %s is only in other, %s", e.table, e.glyph, e.code))
+			if err := v.AddCompound(e.glyph, e.code); err != nil {
+				return nil, nil, err
+			}
+		default:
+			diffs = append(diffs, fmt.Sprintf("%s: %s is only in other, %s (not applied)", e.table, e.glyph, e.code))
+		}
+	}
+	full := isFull(other)
+	for _, e := range ours {
+		if full && !historicTables[e.table] && !seen[e.glyph] {
+			diffs = append(diffs, fmt.Sprintf("%s: %s is only in odiphone, %s", e.table, e.glyph, e.code))
+		}
+	}
+	sort.Strings(diffs)
+	return v, diffs, nil
+}
+
+// isFull reports if a dump has every table of tables.txt, ie: is not a
+// partial dump of only the entries that are known to differ.
+func isFull(es []entry) bool {
+	tables := make(map[string]bool)
+	for _, e := range es {
+		tables[e.table] = true
+	}
+	return tables["vowels"] && tables["consonants"] && tables["compounds"] && tables["modifiers"]
+}
+
+func readDumpFile(path string) ([]entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	es, err := readDump(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return es, nil
+}
+
+// readDump parses the tab separated lines of the table name, glyph, its
+// codepoints, and its code of a table dump. Blank lines and lines starting
+// with # are skipped.
+func readDump(r io.Reader) ([]entry, error) {
+	var (
+		es []entry
+		s  = bufio.NewScanner(r)
+		n  int
+	)
+	for s.Scan() {
+		n++
+		l := s.Text()
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		p := strings.Split(l, "\t")
+		if len(p) != 4 {
+			return nil, fmt.Errorf("line %d: not a table entry: %q", n, l)
+		}
+		es = append(es, entry{table: p[0], glyph: p[1], code: p[3]})
+	}
+	return es, s.Err()
+}
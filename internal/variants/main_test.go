@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/soumendrak/odiphone"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariants(t *testing.T) {
+	// The known divergence of the vendored odphone entries is reported, and
+	// only the words with it differ (and not ଦୈବ, of the vowel sign ୈ).
+	var b bytes.Buffer
+	err := run([]string{"-tables", "../../tables.txt", "-other", "testdata/odphone.txt"}, strings.NewReader("ଘର ଐରାବତ\nଭ୍ରମର ଦୈବ"), &b)
+	require.NoError(t, err)
+	require.Equal(t, "vowels: ଐ = AI, other EI\n"+
+		"ଐରାବତ\tAIRBT AIR1BT AIR1BT\tEIRBT EIR1BT EIR1BT\n", b.String())
+
+	require.Error(t, run(nil, strings.NewReader(""), &b))
+}
+
+func TestVariant(t *testing.T) {
+	ours, err := readDumpFile("../../tables.txt")
+	require.NoError(t, err)
+
+	// The tables agree with themselves.
+	v, diffs, err := variant(odiphone.New(), ours, ours)
+	require.NoError(t, err)
+	require.Empty(t, diffs)
+	require.Equal(t, odiphone.New().EncodeKeys("ଭ୍ରମର"), v.EncodeKeys("ଭ୍ରମର"))
+
+	// A full dump reports the entries that it lacks, and a compound that is
+	// only in it is applied.
+	other, err := readDump(strings.NewReader("# Other.\n" +
+		"vowels\tଇ\tU+0B07\tE\n" +
+		"consonants\tକ\tU+0B15\tK\n" +
+		"compounds\tମ୍ର\tU+0B2E U+0B4D U+0B30\tMR\n" +
+		"modifiers\t୍\tU+0B4D\t2\n"))
+	require.NoError(t, err)
+	v, diffs, err = variant(odiphone.New(), ours, other)
+	require.NoError(t, err)
+	require.Contains(t, diffs, "vowels: ଇ = I, other E")
+	require.Contains(t, diffs, "compounds: ମ୍ର is only in other, MR")
+	require.Contains(t, diffs, "consonants: ଖ is only in odiphone, KH")
+	require.NotContains(t, diffs, "consonants: କ is only in odiphone, K")
+	require.Equal(t, "EMR", v.EncodeKeys("ଇମ୍ର").Key2)
+
+	_, err = readDump(strings.NewReader("vowels\tଇ\tI\n"))
+	require.Error(t, err)
+}
@@ -0,0 +1,85 @@
+// Package keydist provides string distance helpers on already computed
+// ODIphone keys, without the encoder and its tables.
+package keydist
+
+import "unicode/utf8"
+
+// Distance returns the Levenshtein edit distance between two phonetic
+// keys, counted in runes.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// CommonPrefixLen returns the number of leading runes that two phonetic keys
+// have in common. It is a cheap estimate of proximity, eg: for pruning
+// candidates in a trie before computing Distance.
+func CommonPrefixLen(a, b string) int {
+	n := 0
+	for _, r := range a {
+		if b == "" {
+			break
+		}
+		rb, size := utf8.DecodeRuneInString(b)
+		if r != rb {
+			break
+		}
+		b = b[size:]
+		n++
+	}
+	return n
+}
+
+// Trigrams returns the unique trigrams of a phonetic key padded with two
+// spaces at the start and one at the end, in the order of their appearance,
+// the same way PostgreSQL's pg_trgm does, eg: GHR = "  G", " GH", "GHR",
+// "HR ". Indexing the trigrams of the keys matches words that sound alike
+// despite typos. An empty key has no trigrams.
+func Trigrams(key string) []string {
+	if key == "" {
+		return nil
+	}
+
+	var (
+		r    = []rune("  " + key + " ")
+		out  = make([]string, 0, len(r)-2)
+		seen = make(map[string]bool, len(r)-2)
+	)
+	for i := 0; i+3 <= len(r); i++ {
+		t := string(r[i : i+3])
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+func minInt(a int, b ...int) int {
+	for _, v := range b {
+		if v < a {
+			a = v
+		}
+	}
+	return a
+}
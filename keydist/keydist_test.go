@@ -0,0 +1,65 @@
+package keydist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		d    int
+	}{
+		{"", "", 0},
+		{"BHRMR", "", 5},
+		{"", "BHRMR", 5},
+		{"BHRMR", "BHRMR", 0},
+		{"BH2RMR", "BH2RMR3", 1},
+		{"BHRMR", "BHRMNH", 2},
+		{"A7SH", "ASH", 1},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.d, Distance(v.a, v.b), v.a+" "+v.b)
+		require.Equal(t, v.d, Distance(v.b, v.a), v.b+" "+v.a)
+	}
+}
+
+func TestCommonPrefixLen(t *testing.T) {
+	tests := []struct {
+		a, b string
+		n    int
+	}{
+		{"", "", 0},
+		{"BHRMR", "", 0},
+		{"BH2RMR", "BH2RMR3", 6},
+		{"BHRMR", "BHRMNH", 4},
+		{"BHRMR", "BHRMR", 5},
+		{"ASH", "BSH", 0},
+		{"BHRM୰R", "BHRM୰", 5},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.n, CommonPrefixLen(v.a, v.b), v.a+" "+v.b)
+		require.Equal(t, v.n, CommonPrefixLen(v.b, v.a), v.b+" "+v.a)
+	}
+}
+
+func TestTrigrams(t *testing.T) {
+	tests := []struct {
+		key      string
+		trigrams []string
+	}{
+		{"", nil},
+		{"A", []string{"  A", " A "}},
+		{"SH", []string{"  S", " SH", "SH "}},
+		{"GHR", []string{"  G", " GH", "GHR", "HR "}},
+		{"BH2RMR", []string{"  B", " BH", "BH2", "H2R", "2RM", "RMR", "MR "}},
+		// Repeated trigrams are returned once.
+		{"RRRR", []string{"  R", " RR", "RRR", "RR "}},
+		// Runes and not bytes.
+		{"ṬK", []string{"  Ṭ", " ṬK", "ṬK "}},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.trigrams, Trigrams(v.key), v.key)
+	}
+}
@@ -0,0 +1,128 @@
+package odiphone
+
+import "sort"
+
+// MatchConfig configures a FuzzyMatcher.
+type MatchConfig struct {
+	// Encoder encodes the dictionary and the queries. It defaults to New()
+	// if nil.
+	Encoder *ODIphone
+
+	// Weights are the weights of key0, key1, and key2 in the score of a
+	// result, as in SimilarityWeighted. A key with a weight of 0 is not used
+	// to match words at all, eg: {0, 1, 1} ignores the broad key0. Negative
+	// weights are treated as 0, and all zero weights as {1, 1, 1}.
+	Weights [3]float64
+
+	// Threshold is the minimum score (0 to 1) of a result. Raising it trades
+	// recall for precision.
+	Threshold float64
+
+	// MaxResults is the maximum number of results. If less than 1, all
+	// results above the threshold are returned.
+	MaxResults int
+}
+
+// Result is a dictionary word matched by a FuzzyMatcher.
+type Result struct {
+	Word string
+
+	// Score is the weighted fraction of key levels at which the word
+	// matches the query, between 0 and 1.
+	Score float64
+
+	// Level is the narrowest key level at which the word matches the query.
+	Level MatchLevel
+}
+
+// FuzzyMatcher finds the dictionary words that sound like a query word,
+// scored by the key levels at which they match. It is built on a Suggester.
+type FuzzyMatcher struct {
+	s   *Suggester
+	cfg MatchConfig
+}
+
+// NewFuzzyMatcher returns a new FuzzyMatcher for the dictionary of words.
+func NewFuzzyMatcher(dict []string, cfg MatchConfig) *FuzzyMatcher {
+	if cfg.Encoder == nil {
+		cfg.Encoder = New()
+	}
+
+	var total float64
+	for i, w := range cfg.Weights {
+		if w < 0 {
+			cfg.Weights[i] = 0
+		}
+		total += cfg.Weights[i]
+	}
+	if total == 0 {
+		cfg.Weights = [3]float64{1, 1, 1}
+	}
+
+	return &FuzzyMatcher{s: NewSuggester(cfg.Encoder, dict), cfg: cfg}
+}
+
+// Add adds a word to the dictionary.
+func (m *FuzzyMatcher) Add(word string) {
+	m.s.Add(word)
+}
+
+// Match returns the dictionary words that match the query with a score of
+// at least the threshold, the highest scores first, and then sorted
+// lexically.
+func (m *FuzzyMatcher) Match(query string) []Result {
+	k := m.cfg.Encoder.EncodeKeys(query)
+	if k.Key0 == "" {
+		return nil
+	}
+
+	// The words in the bucket of a key of the query match it at that level.
+	var (
+		keys  = [3]string{k.Key0, k.Key1, k.Key2}
+		match = make(map[string]*[3]bool)
+		order []string
+	)
+	for i, key := range keys {
+		if m.cfg.Weights[i] == 0 {
+			continue
+		}
+		for _, w := range m.s.buckets[i][key] {
+			if match[w] == nil {
+				match[w] = new([3]bool)
+				order = append(order, w)
+			}
+			match[w][i] = true
+		}
+	}
+
+	var total float64
+	for _, w := range m.cfg.Weights {
+		total += w
+	}
+
+	out := make([]Result, 0, len(order))
+	for _, w := range order {
+		r := Result{Word: w}
+		for i, ok := range match[w] {
+			if ok {
+				r.Score += m.cfg.Weights[i]
+				r.Level = MatchLevel(i + 1)
+			}
+		}
+		r.Score /= total
+		if r.Score >= m.cfg.Threshold {
+			out = append(out, r)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Word < out[j].Word
+	})
+	if m.cfg.MaxResults > 0 && len(out) > m.cfg.MaxResults {
+		out = out[:m.cfg.MaxResults]
+	}
+	return out
+}
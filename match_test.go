@@ -0,0 +1,50 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyMatcher(t *testing.T) {
+	m := NewFuzzyMatcher(dict, MatchConfig{})
+	require.Equal(t, []Result{
+		{"ଭ୍ରମର", 1, MatchKey2},
+		{"ଭ୍ରମରେ", 1.0 / 3, MatchKey0},
+	}, m.Match("ଭ୍ରମର"))
+	require.Equal(t, []Result{
+		{"ଲଖ", 1, MatchKey2},
+		{"ଲକ୍ଷ", 2.0 / 3, MatchKey1},
+	}, m.Match("ଲଖ"))
+	require.Empty(t, m.Match("ଘର"))
+	require.Empty(t, m.Match("hello"))
+
+	// Words added later are matched.
+	m.Add("ଘର")
+	require.Equal(t, []Result{{"ଘର", 1, MatchKey2}}, m.Match("ଘର"))
+}
+
+func TestFuzzyMatcherThreshold(t *testing.T) {
+	// The threshold drops the weaker matches.
+	m := NewFuzzyMatcher(dict, MatchConfig{Threshold: 0.5})
+	require.Equal(t, []Result{{"ଭ୍ରମର", 1, MatchKey2}}, m.Match("ଭ୍ରମର"))
+	require.Len(t, m.Match("ଲଖ"), 2)
+
+	m = NewFuzzyMatcher(dict, MatchConfig{Threshold: 0.7})
+	require.Equal(t, []Result{{"ଲଖ", 1, MatchKey2}}, m.Match("ଲଖ"))
+
+	// Unused keys do not match words.
+	m = NewFuzzyMatcher(dict, MatchConfig{Weights: [3]float64{0, 0, 1}})
+	require.Equal(t, []Result{{"ଲଖ", 1, MatchKey2}}, m.Match("ଲଖ"))
+	m = NewFuzzyMatcher(dict, MatchConfig{Weights: [3]float64{0, 1, 1}})
+	require.Equal(t, []Result{{"ଲଖ", 1, MatchKey2}, {"ଲକ୍ଷ", 0.5, MatchKey1}}, m.Match("ଲଖ"))
+}
+
+func TestFuzzyMatcherMaxResults(t *testing.T) {
+	m := NewFuzzyMatcher(dict, MatchConfig{MaxResults: 1})
+	require.Equal(t, []Result{{"ଭ୍ରମର", 1, MatchKey2}}, m.Match("ଭ୍ରମର"))
+	require.Equal(t, []Result{{"ଲଖ", 1, MatchKey2}}, m.Match("ଲଖ"))
+
+	m = NewFuzzyMatcher(dict, MatchConfig{MaxResults: 5})
+	require.Len(t, m.Match("ଭ୍ରମର"), 2)
+}
@@ -24,9 +24,19 @@
 // Soumendra Kumar Sahoo (c) 2024. https://www.soumendrak.com | License: GPLv3
 package odiphone
 
+//go:generate go run ./internal/gen -src odiphone.go -o tables.txt
+
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 var vowels = map[string]string{
@@ -36,10 +46,12 @@ var vowels = map[string]string{
 	"ଈ": "EE",
 	"ଉ": "U",
 	"ଊ": "OO",
-	"ଋ": "RU",
-	"ୠ": "ROO",
+	// The vocalic vowels ଋ (ṛ) and ୠ (ṝ) are pronounced "ru" and "rū", and
+	// are variants of ର.
+	"ଋ": "R:U",
+	"ୠ": "R:O",
 	"ଏ": "E",
-	"ଐ": "EI",
+	"ଐ": "AI",
 	"ଓ": "O",
 	"ଔ": "OU",
 }
@@ -54,12 +66,14 @@ var consonants = map[string]string{
 	"ଛ": "CHH",
 	"ଜ": "J",
 	"ଝ": "JH",
-	"ଞ": "NY",
-	"ଟ": "TT",
-	"ଠ": "TTH",
-	"ଡ": "DD",
-	"ଢ": "DDH",
-	"ଣ": "NH",
+	"ଞ": "N:Y",
+	// The retroflexes are variants of the dentals, which they are commonly
+	// confused with.
+	"ଟ": "T:R",
+	"ଠ": "TH:R",
+	"ଡ": "D:R",
+	"ଢ": "DH:R",
+	"ଣ": "N:R",
 	"ତ": "T",
 	"ଥ": "TH",
 	"ଦ": "D",
@@ -73,22 +87,37 @@ var consonants = map[string]string{
 	"ଯ": "J",
 	"ର": "R",
 	"ଲ": "L",
-	"ଳ": "LH",
-	"ଵ": "W",
+	"ଳ": "L:R",
+	// ଵ (va) and ୱ (wa) are commonly interchanged with ବ.
+	"ଵ": "B:V",
 	"ଶ": "SH",
 	"ଷ": "SH",
 	"ସ": "S",
-	"ହ": "H",
+	// ହ is not H, so that a consonant and ହ are not its aspirate, eg: କହ is
+	// not ଖ.
+	"ହ": "HH",
 	"ୟ": "Y",
-	"ୱ": "WA",
+	"ୱ": "B:W",
 }
 
 var compounds = map[string]string{
-	"କ୍ତ": "KT",
-	"ଙ୍କ": "NK",
-	"ଙ୍ଗ": "NG",
-	"ଙ୍ଘ": "NGH",
-	"ଞ୍ଜ": "NJ",
+	// କ୍ଷ (kṣa) is colloquially pronounced "kh(ya)".
+	"କ୍ଷ": "KH:S",
+	// The nasals ଙ and ଞ are pronounced ନ in a cluster.
+	"ଙ୍କ": "N2K",
+	"ଙ୍ଗ": "N2G",
+	"ଙ୍ଘ": "N2GH",
+	"ଞ୍ଜ": "N2J",
+	// ଙ୍କ୍ଷ (ṅkṣa) is matched as a whole instead of ଙ୍କ and a stray ଷ.
+	"ଙ୍କ୍ଷ": "N2KH:S",
+	// ଶ୍ର is pronounced "sr", eg: ଶ୍ରୀ = srī, and is a variant of ସ୍ର.
+	"ଶ୍ର": "S:H2R",
+
+	// ଡ଼ (ṛa) and ଢ଼ (ṛha) are retroflex flaps, which NFC decomposes into ଡ and
+	// ଢ with a nukta. Like the stops, they are variants of the dentals, as
+	// the flaps are commonly written without the nukta.
+	"\u0b21\u0b3c": "D:F",
+	"\u0b22\u0b3c": "DH:F",
 }
 
 var modifiers = map[string]string{
@@ -105,126 +134,1521 @@ var modifiers = map[string]string{
 	"ୀ": "5",
 	"ୁ": "6",
 	"ୂ": "6",
-	"ୃ": "6",
+	// ୃ (vocalic r) is pronounced "ru".
+	"ୃ": "6:R",
 	"ଃ": "7",
 	"ଁ": "7",
 	"ଂ": "7",
-	"ୄ": "8",
+	// ୄ (vocalic rr) is pronounced "rū".
+	"ୄ": "8:R",
 	"ଽ": "8",
 }
 
+// historicVowels and historicModifiers are the archaic vowels and vowel signs
+// of the Oriya block that are not used in modern Odia, but are found in
+// digitized historic texts. They are only mapped with Options.Historic, and
+// are otherwise unmapped and reported by UnmappedRunes. The vocalic l is
+// pronounced "lu", like the vocalic r is "ru", and its vowels and signs are
+// coded like those of the vocalic r. Other archaic characters and
+// symbols (eg: the isshar ୰ and the fraction signs) are never mapped.
+var historicVowels = map[string]string{
+	"ଌ": "L:U",
+	"ୡ": "L:O",
+}
+
+var historicModifiers = map[string]string{
+	"ୢ": "6:L",
+	"ୣ": "8:L",
+}
+
+// variant is the marker of a variant code: a code followed by the marker and
+// a letter, eg: ୱ = B:W is a variant of ବ = B, and ୃ = 6:R of ୁ = 6. A
+// variant is kept distinct from its code in key2, and merged with it in key0
+// and key1, which drop the marker and its letter.
+const variant = ':'
+
+// sharedCodes are the glyphs of the tables that have the same code by
+// design, as the algorithm does not tell them apart: the consonants that are
+// pronounced the same, the short and long forms of a vowel sign, the e and
+// ai signs with the length marks, the nasal signs, and the halant and the
+// nukta that both modify the consonant before them. Other glyphs with the
+// same code are reported by codeCollisions.
+var sharedCodes = [][]string{
+	{"ଜ", "ଯ"},
+	{"ଶ", "ଷ"},
+	{"ି", "ୀ"},
+	{"ୁ", "ୂ"},
+	{"େ", "ୈ", "ୖ", "ୗ"},
+	{"ୋ", "ୌ"},
+	{"ଁ", "ଂ", "ଃ"},
+	{"୍", "଼"},
+}
+
+// spelledCodes are the glyphs of the tables whose code is by design the
+// concatenation of the codes of another spelling: the compounds of the
+// nasals that are pronounced ନ in a cluster, the diphthongs, and the long
+// vowels, which are not spelt as doubled short ones in Odia. Other glyphs
+// with the code of a sequence of glyphs are reported by codeCollisions.
+var spelledCodes = map[string]string{
+	"ଙ୍କ":   "ନ୍କ",
+	"ଙ୍ଗ":   "ନ୍ଗ",
+	"ଙ୍ଘ":   "ନ୍ଘ",
+	"ଞ୍ଜ":   "ନ୍ଜ",
+	"ଙ୍କ୍ଷ": "ନ୍କ୍ଷ",
+	"ଐ":     "ଅଇ",
+	"ଔ":     "ଓଉ",
+	"ଆ":     "ଅଅ",
+	"ଈ":     "ଏଏ",
+	"ଊ":     "ଓଓ",
+}
+
+// aspiration is the marker of aspiration with Options.SplitAspiration.
+const aspiration = "ʰ"
+
+// aspirated are the codes of aspirated consonants and their unaspirated
+// bases, longest first.
+var aspirated = [][2]string{
+	{"CHH", "CH"},
+	{"KH", "K"},
+	{"GH", "G"},
+	{"JH", "J"},
+	{"TH", "T"},
+	{"DH", "D"},
+	{"PH", "P"},
+	{"BH", "B"},
+}
+
+// inherentVowel is the code for the inherent vowel (schwa) of a consonant
+// with Options.InherentVowel.
+const inherentVowel = "9"
+
+// hardSound is the code for a hard sound (aspiration, gemination, or the
+// nukta of a flap) with Options.HardSounds.
+const hardSound = "0"
+
+const (
+	halant     = '୍'
+	nukta      = '଼'
+	vowelSigns = "ାିୀୁୂୃୄେୈୋୌୖୗ"
+)
+
+// yaPhala is the code for the palatalizing ya-phala (consonant + ୍ + ଯ/ୟ)
+// that is attached to the preceding consonant, as in ବିଦ୍ୟା (B5DY21).
+const yaPhala = "Y2"
+
+// overline is the Oriya sign overline that some inputs put around the halant
+// of a conjunct to request its stacked form.
+const overline = "\u0b55"
+
+// doubleHalant is a halant typed twice, which is reduced to one.
+const doubleHalant = "୍୍"
+
+// confusables maps codepoints and sequences that render identically to a
+// canonical Odia glyph (a common typing or OCR error) to that glyph. These
+// include the Devanagari signs that Odia fonts render for lack of their own
+// and vowels typed as a vowel and a sign.
+var confusables = newReplacer(
+	"\u0901", "ଁ",
+	"\u0902", "ଂ",
+	"\u0903", "ଃ",
+	"\u093c", "଼",
+	"\u094d", "୍",
+	"ଅା", "ଆ",
+	"ଏୖ", "ଐ",
+	"ଓୗ", "ଔ",
+)
+
+// hConjuncts reorders conjuncts of ହ and a nasal or ଲ in which the h is
+// pronounced after the consonant, eg: ବ୍ରାହ୍ମଣ = brāmhaṇa and ଚିହ୍ନ = chinha.
+var hConjuncts = newReplacer(
+	"ହ୍ମ", "ମ୍ହ",
+	"ହ୍ନ", "ନ୍ହ",
+	"ହ୍ଣ", "ଣ୍ହ",
+	"ହ୍ଲ", "ଲ୍ହ",
+)
+
+// replacer is a strings.Replacer that returns an input that has none of the
+// strings it replaces as is, without allocating.
+type replacer struct {
+	*strings.Replacer
+	olds []string
+}
+
+func newReplacer(oldnew ...string) *replacer {
+	r := &replacer{Replacer: strings.NewReplacer(oldnew...)}
+	for i := 0; i < len(oldnew); i += 2 {
+		r.olds = append(r.olds, oldnew[i])
+	}
+	return r
+}
+
+// Replace returns a copy of s with all replacements performed, or s if it
+// has nothing to replace.
+func (r *replacer) Replace(s string) string {
+	for _, old := range r.olds {
+		if strings.Contains(s, old) {
+			return r.Replacer.Replace(s)
+		}
+	}
+	return s
+}
+
+// geminates drops the halant between two identical consonants so that a
+// geminate is encoded as the doubled consonant, eg: ଅନ୍ନ = ANN.
+var geminates = newGeminateReplacer(func(c string) string { return c + c })
+
+// hardGeminates replaces a geminate with the consonant and the hard sound
+// code for Options.HardSounds, as a variant that keeps it distinct from an
+// aspirate in key2, eg: ଅକ୍କ = AK0:G and ଅଖ = AK0.
+var hardGeminates = newGeminateReplacer(func(c string) string { return c + hardSound + string(variant) + "G" })
+
+func newGeminateReplacer(geminate func(c string) string) *strings.Replacer {
+	var pairs []string
+	for _, c := range longestFirst(consonants) {
+		pairs = append(pairs, c+string(halant)+c, geminate(c))
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// vowelGlides inserts the glide that is pronounced between an i or u vowel
+// and a following independent vowel, so that the common spellings with and
+// without the glide are the same, eg: ନୁଆ = ନୁୱା and ଦିଆ = ଦିୟା.
+var vowelGlides = newGlideReplacer()
+
+func newGlideReplacer() *strings.Replacer {
+	var pairs []string
+	for _, v := range []string{"ି", "ୀ", "ଇ", "ଈ"} {
+		pairs = append(pairs, v+"ଅ", v+"ୟ", v+"ଆ", v+"ୟା", v+"ଉ", v+"ୟୁ", v+"ଏ", v+"ୟେ", v+"ଓ", v+"ୟୋ")
+	}
+	for _, v := range []string{"ୁ", "ୂ", "ଉ", "ଊ"} {
+		pairs = append(pairs, v+"ଅ", v+"ୱ", v+"ଆ", v+"ୱା", v+"ଇ", v+"ୱି", v+"ଏ", v+"ୱେ")
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// nasalClusters rewrites the nasal of a cluster of a nasal and a homorganic
+// stop to ନ, eg: ଙ୍କ = ନ୍କ and ମ୍ପ = ନ୍ପ.
+var nasalClusters = newNasalClusterReplacer()
+
+func newNasalClusterReplacer() *strings.Replacer {
+	var pairs []string
+	for _, c := range longestFirst(homorganicNasals) {
+		pairs = append(pairs, homorganicNasals[c]+string(halant)+c, "ନ"+string(halant)+c)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
+// elideGlides drops the glide ୟ between two vowels, ie: after a vowel or a
+// consonant that is not in a conjunct and before a vowel sign or another
+// consonant, with its vowel sign, if any, eg: ମାୟା = ମା and ନୟନ = ନନ. The
+// glides pronounced between vowels are inserted first so that the spellings
+// with and without them are the same, eg: ଦିଆ = ଦିୟା = ଦି.
+func elideGlides(input string) string {
+	input = vowelGlides.Replace(input)
+
+	var (
+		b  strings.Builder
+		rs = []rune(input)
+	)
+	b.Grow(len(input))
+	for i := 0; i < len(rs); i++ {
+		if rs[i] == 'ୟ' && i > 0 && i+1 < len(rs) && afterVowel(rs[i-1]) {
+			switch next := rs[i+1]; {
+			case strings.ContainsRune(vowelSigns, next):
+				i++
+				continue
+			case isConsonant(next):
+				continue
+			}
+		}
+		b.WriteRune(rs[i])
+	}
+	return b.String()
+}
+
+// afterVowel reports if a rune is followed by a vowel sound unless it is
+// followed by a halant: a vowel, a vowel sign, or a consonant with the
+// inherent vowel.
+func afterVowel(r rune) bool {
+	return isVowel(r) || strings.ContainsRune(vowelSigns, r) || isConsonant(r) || r == nukta
+}
+
+// homorganicNasals are the nasal consonants of the classes of stops by
+// their place of articulation, keyed by the stops.
+var homorganicNasals = map[string]string{
+	// Velar.
+	"କ": "ଙ", "ଖ": "ଙ", "ଗ": "ଙ", "ଘ": "ଙ",
+	// Palatal.
+	"ଚ": "ଞ", "ଛ": "ଞ", "ଜ": "ଞ", "ଝ": "ଞ",
+	// Retroflex.
+	"ଟ": "ଣ", "ଠ": "ଣ", "ଡ": "ଣ", "ଢ": "ଣ",
+	// Dental.
+	"ତ": "ନ", "ଥ": "ନ", "ଦ": "ନ", "ଧ": "ନ",
+	// Labial.
+	"ପ": "ମ", "ଫ": "ମ", "ବ": "ମ", "ଭ": "ମ",
+}
+
+// anusvaras rewrites an anusvara before a stop to the homorganic nasal and a
+// halant, eg: ଶଂକର = ଶଙ୍କର. The flaps ଡ଼ and ଢ଼ are not stops and are
+// left as is, as are the others (eg: ଅଂଶ).
+var anusvaras = newAnusvaraReplacer()
+
+func newAnusvaraReplacer() *strings.Replacer {
+	// The replacer compares in argument order, so the flaps come first.
+	pairs := []string{"ଂ\u0b21\u0b3c", "ଂ\u0b21\u0b3c", "ଂ\u0b22\u0b3c", "ଂ\u0b22\u0b3c"}
+	for _, c := range longestFirst(homorganicNasals) {
+		pairs = append(pairs, "ଂ"+c, homorganicNasals[c]+string(halant)+c)
+	}
+	return strings.NewReplacer(pairs...)
+}
+
 var (
-	regexKey0, _     = regexp.Compile(`[1-8]`)
-	regexKey1, _     = regexp.Compile(`[7-8]`)
-	regexNonOdia, _  = regexp.Compile(`\P{Oriya}`)
-	regexAlphaNum, _ = regexp.Compile(`[^\dA-Z]`)
+	regexNonOdia, _ = regexp.Compile(`\P{Oriya}`)
+	regexHalants, _ = regexp.Compile(`୍{2,}`)
+)
+
+// Options configures an ODIphone tokenizer. The zero value is the default
+// configuration.
+type Options struct {
+	// Strict makes TryEncode return an *UnmappedError when the input has
+	// Odia characters that are not in any of the phonetic tables.
+	Strict bool
+
+	// KeepUnmapped retains the Odia characters that are not in any of the
+	// phonetic tables verbatim in all three keys, eg: ଭ୍ରମ୰ର = BH2RM୰R,
+	// instead of dropping them.
+	KeepUnmapped bool
+
+	// BatchCache memoizes the keys of repeated words within a single call to
+	// EncodeMany, EncodeScanner or EncodeReader. The cache is discarded at
+	// the end of the call. It is off by default, as it only pays off for
+	// inputs of many repeated words, and slows down inputs of distinct ones.
+	BatchCache bool
+
+	// InherentVowel marks consonants that carry the inherent vowel (schwa),
+	// ie: those that are not followed by a vowel sign or halant, with the
+	// code 9 in key2, eg: ଘର = GH9R9. key0 and key1 are unaffected.
+	InherentVowel bool
+
+	// SchwaDeletion does not mark the inherent vowel of a word-final
+	// consonant with InherentVowel, eg: ଘର = GH9R.
+	SchwaDeletion bool
+
+	// LoanwordSchwaDeletion does not mark the inherent vowel of a word-final
+	// consonant with InherentVowel in words that end in a cluster typical of
+	// English loanwords, in which the final consonant has no schwa: a
+	// retroflex ଟ or ଡ (for the English t and d) after a consonant that is
+	// not retroflex, eg: ପୋସ୍ଟ (post) = P4S2T:R and କାର୍ଡ (card) = K1R2D:R. In
+	// native words, the retroflex stops only cluster with retroflex
+	// consonants, eg: ଦଣ୍ଡ = D9N:R2D:R9. It is a heuristic: loanwords spelt
+	// with native clusters (eg: ଟେଷ୍ଟ) keep the final schwa.
+	LoanwordSchwaDeletion bool
+
+	// VelarNasal is the code for the velar nasal ଙ, eg: "Ṅ". It defaults to
+	// "WN" if empty. Compounds with ଙ (ଙ୍କ, ଙ୍ଗ, ଙ୍ଘ) are unaffected, as the
+	// ଙ of a cluster is pronounced ନ. A code that is the concatenation of
+	// other codes (eg: "NG" of ନଗ) encodes ଙ the same as them, and is
+	// rejected by Validate.
+	VelarNasal string
+
+	// SplitAspiration represents aspiration as a separate marker (ʰ) after
+	// the unaspirated consonant instead of baking it into the consonant's
+	// code, eg: ଖ = Kʰ and ଥ = Tʰ instead of KH and TH. This lines up
+	// aspirated and unaspirated consonants in alignments.
+	SplitAspiration bool
+
+	// Dialect selects a regional pronunciation profile that tweaks a few
+	// mappings. The zero value is DialectStandard.
+	Dialect Dialect
+
+	// DisableNFC does not convert the input to the NFC form before encoding,
+	// which otherwise makes differently composed glyphs (eg: େ + ା and ୋ) the
+	// same.
+	DisableNFC bool
+
+	// DisableConfusables does not map the codepoints and sequences that
+	// render identically to an Odia glyph (eg: the Devanagari virama for the
+	// halant) to the glyph before encoding.
+	DisableConfusables bool
+
+	// Sentinel, if set, is returned as all three keys of an input that has
+	// no Odia characters (eg: an English word) instead of empty keys, so that
+	// such inputs can be told apart and filtered downstream, eg: "∅". It is
+	// returned as is, without a checksum.
+	Sentinel string
+
+	// DisableGeminates encodes a halant between two identical consonants,
+	// ie: a geminate, as a cluster with the halant (eg: ନ୍ତ = N2T and ନ୍ନ =
+	// N2N), and not as the doubled consonant in key2 and key1 (eg: ନ୍ନ = NN).
+	DisableGeminates bool
+
+	// OCRMatras reinterprets an independent vowel that directly follows a
+	// consonant as its vowel sign, eg: କଆ = କା, a common OCR error. It is off
+	// by default as some words (eg: ନଈ) are spelt so.
+	OCRMatras bool
+
+	// MaxKeyLen, if set, truncates each key to at most MaxKeyLen runes, eg:
+	// for fixed width columns. Keys are truncated at the boundary of a
+	// phoneme (a glyph code and its modifier codes) so that a code is never
+	// cut midway. Truncation is lossy: words that differ after the first
+	// few phonemes have the same keys.
+	MaxKeyLen int
+
+	// Historic maps the archaic vowels and vowel signs found in historic
+	// texts (ଌ, ୡ, ୢ, and ୣ) instead of treating them as unmapped.
+	Historic bool
+
+	// HomorganicNasal rewrites an anusvara before a stop to the nasal
+	// consonant of the stop's place of articulation before encoding, eg:
+	// ଶଂକର = ଶଙ୍କର (SH7KR = SHN2KR), so that the two spellings have the same
+	// keys. An anusvara before other consonants (eg: ଅଂଶ) is unaffected.
+	HomorganicNasal bool
+
+	// Checksum appends a checksum character (a to z) to each non-empty key,
+	// eg: ଘର = GHRi, so that keys corrupted in transit or storage can be
+	// detected with Verify. The checksum counts toward MaxKeyLen: keys are
+	// truncated to MaxKeyLen-1 runes before it. It is not a phonetic code:
+	// strip it with StripChecksum before comparing keys by their distance or
+	// prefix. The Sentinel has no checksum.
+	Checksum bool
+
+	// NasalClusters encodes the nasal of a cluster of a nasal and a
+	// homorganic stop (eg: ଙ୍କ, ଞ୍ଚ, ଣ୍ଟ, ନ୍ତ, ମ୍ପ) as the generic N in
+	// key0, eg: ଚମ୍ପା = CHNP and not CHMP, so that the clusters match
+	// however their nasal is written. key1 and key2 are unaffected.
+	NasalClusters bool
+
+	// VisualOrder reads the pre-base vowel signs (େ and ୈ) as stored in
+	// visual order, ie: before their consonant as rendered, and moves them
+	// after it before encoding, eg: େକ = କେ. It is for inputs from sources
+	// that store text so, as it misreads text in the standard logical order
+	// where a pre-base sign follows a consonant and precedes another one.
+	VisualOrder bool
+
+	// GlideElision drops the glide ୟ between two vowels from key0, eg:
+	// ମାୟା = M and ନୟନ = NN, so that words that differ by the glide alone
+	// match. key1 and key2 are unaffected. ୟ in a conjunct (eg: ସତ୍ୟ) or at
+	// the end of a word (eg: ଭୟ) is not a glide and is kept.
+	GlideElision bool
+
+	// HardSounds encodes the hard sounds as the modifier code 0 after the
+	// plain sound, so that key1 accounts for them and key0 does not: the
+	// aspiration of a consonant (eg: ଖ = K0), a geminate (eg: ଅନ୍ନ = AN0:G),
+	// and the nukta of a flap (eg: ଡ଼ = D0:F). key0 then merges words that
+	// differ only by hard sounds, eg: ଦୁଃଖ = DK, D6K0, and D67K0, including
+	// the minimal pairs of a word-initial aspirate (eg: ଫଳ and ପଳ) unless
+	// with InitialAspiration. It applies to geminates even with
+	// DisableGeminates, and SplitAspiration takes precedence over it.
+	HardSounds bool
+
+	// InitialAspiration keeps the hard sound of the aspiration of a
+	// word-initial consonant in key0 with HardSounds, as it is phonemic
+	// word-initially, eg: ଫଳ = P0L and ପଳ = PL. Without HardSounds, the
+	// aspiration is kept in every key.
+	InitialAspiration bool
+}
+
+// Dialect is a regional pronunciation profile of Odia.
+type Dialect int
+
+const (
+	// DialectStandard is standard Odia.
+	DialectStandard Dialect = iota
+
+	// DialectCoastal is the coastal (Cuttack, Puri) pronunciation in which
+	// the sibilants ଶ, ଷ, and ସ are all pronounced s.
+	DialectCoastal
+
+	// DialectWestern is the western (Sambalpuri) pronunciation in which the
+	// sibilants are pronounced s, ଣ is pronounced as ନ, and ଳ as ଲ. It deletes
+	// the word-final schwa with Options.InherentVowel.
+	DialectWestern
 )
 
+// dialects are the consonant mappings overridden by dialects.
+var dialects = map[Dialect]map[string]string{
+	DialectCoastal: {
+		"ଶ": "S",
+		"ଷ": "S",
+	},
+	DialectWestern: {
+		"ଶ": "S",
+		"ଷ": "S",
+		"ଣ": "N",
+		"ଳ": "L",
+	},
+}
+
+// DefaultOptions returns the options used by New(), ie: the zero Options.
+func DefaultOptions() Options {
+	return Options{}
+}
+
+// UnmappedError is returned by TryEncode in strict mode when the input has
+// Odia characters that are not in any of the phonetic tables.
+type UnmappedError struct {
+	Word  string
+	Runes []rune
+}
+
+func (e *UnmappedError) Error() string {
+	return fmt.Sprintf("odiphone: unmapped characters in %q: %U", e.Word, e.Runes)
+}
+
 // ODIphone is the Odia-phone tokenizer.
 type ODIphone struct {
-	modCompounds  *regexp.Regexp
-	modConsonants *regexp.Regexp
-	modVowels     *regexp.Regexp
+	opt Options
+
+	// The phonetic tables of the tokenizer.
+	vowels     map[string]string
+	consonants map[string]string
+	compounds  map[string]string
+	modifiers  map[string]string
+
+	// compoundList are the compounds longest first, and then lexically.
+	compoundList []string
+
+	// glyphs are the codes of the vowels and consonants, and mods the codes
+	// of the modifiers, indexed by their offset in the Oriya block.
+	glyphs [0x80]string
+	mods   [0x80]string
+
+	// known is the set of all runes in the phonetic tables.
+	known map[rune]bool
+
+	// simple are the glyphs of the Oriya block that words are encoded with
+	// by the fast path, indexed by their offset in the block.
+	simple [0x80]simpleGlyph
+
+	// exceptions are words with hand-tuned keys that bypass the algorithm.
+	exceptions map[string]Keys
+
+	// aspirates are the glyphs whose aspiration is a hard sound, with
+	// Options.HardSounds.
+	aspirates []string
 }
 
-// New returns a new instance of the ODIphone tokenizer.
-func New() *ODIphone {
-	var (
-		glyphs []string
-		mods   []string
-		od     = &ODIphone{}
-	)
+// New returns a new instance of the ODIphone tokenizer configured with
+// DefaultOptions() and then the given options in order.
+func New(opts ...Option) *ODIphone {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return NewWithOptions(o)
+}
 
-	// modifiers.
-	for m := range modifiers {
-		mods = append(mods, m)
+// NewWithOptions returns a new instance of the ODIphone tokenizer
+// configured with the given options.
+func NewWithOptions(o Options) *ODIphone {
+	od := &ODIphone{
+		opt:        o,
+		vowels:     copyTable(vowels),
+		consonants: copyTable(consonants),
+		compounds:  copyTable(compounds),
+		modifiers:  copyTable(modifiers),
+		exceptions: make(map[string]Keys),
 	}
 
-	// compounds.
-	for c := range compounds {
-		glyphs = append(glyphs, c)
+	for k, v := range dialects[o.Dialect] {
+		od.consonants[k] = v
+	}
+	if o.Dialect == DialectWestern {
+		od.opt.SchwaDeletion = true
 	}
-	od.modCompounds, _ = regexp.Compile(`((` + strings.Join(glyphs, "|") + `)(` + strings.Join(mods, "|") + `))`)
 
-	// consonants.
-	glyphs = []string{}
-	for k := range consonants {
-		glyphs = append(glyphs, k)
+	if o.Historic {
+		for k, v := range historicVowels {
+			od.vowels[k] = v
+		}
+		for k, v := range historicModifiers {
+			od.modifiers[k] = v
+		}
 	}
-	od.modConsonants, _ = regexp.Compile(`((` + strings.Join(glyphs, "|") + `)(` + strings.Join(mods, "|") + `))`)
 
-	// vowels.
-	glyphs = []string{}
-	for k := range vowels {
-		glyphs = append(glyphs, k)
+	if o.VelarNasal != "" {
+		od.consonants["ଙ"] = o.VelarNasal
+	}
+	if o.SplitAspiration {
+		splitAspiration(od.consonants, aspiration)
+		splitAspiration(od.compounds, aspiration)
+	}
+	if o.HardSounds {
+		od.aspirates = append(splitAspiration(od.consonants, hardSound), splitAspiration(od.compounds, hardSound)...)
+		for k, v := range od.compounds {
+			if i := strings.IndexByte(v, variant); i >= 0 && strings.HasSuffix(k, string(nukta)) {
+				od.compounds[k] = v[:i] + hardSound + v[i:]
+			}
+		}
 	}
-	od.modVowels, _ = regexp.Compile(`((` + strings.Join(glyphs, "|") + `)(` + strings.Join(mods, "|") + `))`)
 
+	od.compile()
 	return od
 }
 
+// compile builds the lookups of the tokenizer from its tables.
+func (od *ODIphone) compile() {
+	od.known = make(map[rune]bool)
+	for _, m := range []map[string]string{od.vowels, od.consonants, od.compounds, od.modifiers} {
+		for k := range m {
+			for _, r := range k {
+				od.known[r] = true
+			}
+		}
+	}
+
+	// Compounds are matched longest first, and then lexically, so that the
+	// precedence of overlapping compounds does not depend on the order of
+	// the table (see matchCompounds).
+	od.compoundList = longestFirst(od.compounds)
+
+	// The vowels, consonants and modifiers are single runes of the Oriya
+	// block (checked by internal/gen), which are looked up by their offset.
+	od.glyphs, od.mods = [0x80]string{}, [0x80]string{}
+	for _, m := range []map[string]string{od.vowels, od.consonants} {
+		for k, v := range m {
+			if r := []rune(k); len(r) == 1 && r[0] >= oriyaBlock && r[0] < oriyaBlock+0x80 {
+				od.glyphs[r[0]-oriyaBlock] = v
+			}
+		}
+	}
+	for k, v := range od.modifiers {
+		if r := []rune(k); len(r) == 1 && r[0] >= oriyaBlock && r[0] < oriyaBlock+0x80 {
+			od.mods[r[0]-oriyaBlock] = v
+		}
+	}
+
+	od.compileSimple()
+}
+
+// splitAspiration replaces the aspirated consonant codes in a table with
+// their unaspirated bases followed by a marker of aspiration.
+func splitAspiration(m map[string]string, marker string) []string {
+	var out []string
+	for k, v := range m {
+		for _, a := range aspirated {
+			if strings.Contains(v, a[0]) {
+				m[k] = strings.Replace(v, a[0], a[1]+marker, 1)
+				out = append(out, k)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// longestFirst returns the glyphs of a table sorted longest first, and then
+// lexically for a deterministic order.
+func longestFirst(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i]) != len(out[j]) {
+			return len(out[i]) > len(out[j])
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
+
+func copyTable(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
 // Encode encodes a unicode Odia string to its Roman ODIphone hash.
 // Ideally, words should be encoded one at a time, and not as phrases
 // or sentences.
 func (od *ODIphone) Encode(input string) (string, string, string) {
-	// key2 accounts for hard and modified sounds.
-	key2 := od.process(input)
+	k := od.encodeNormalized(od.normalize(input))
+	return k.Key0, k.Key1, k.Key2
+}
+
+// encodeNormalized encodes a normalized input.
+func (od *ODIphone) encodeNormalized(input string) Keys {
+	if k, ok := od.fixedKeys(input); ok {
+		return k
+	}
+
+	var (
+		buf [3][64]byte
+		dst = [3][]byte{buf[0][:0], buf[1][:0], buf[2][:0]}
+	)
+	dst = od.encodeTo(dst, input)
+	key2 := string(dst[2])
+	return Keys{Key0: keyString(dst[0], key2), Key1: keyString(dst[1], key2), Key2: key2}
+}
+
+// keyString returns a key as a string, reusing key2 if they are the same.
+func keyString(key []byte, key2 string) string {
+	if string(key) == key2 {
+		return key2
+	}
+	return string(key)
+}
+
+// fixedKeys returns the keys of a normalized input that are not encoded by
+// the algorithm: the keys of an exception, or the sentinel of an empty input.
+// They are truncated to Options.MaxKeyLen at rune boundaries.
+func (od *ODIphone) fixedKeys(input string) (Keys, bool) {
+	if k, ok := od.exceptions[input]; ok {
+		n := od.keyLimit()
+		k = Keys{truncateRunes(k.Key0, n), truncateRunes(k.Key1, n), truncateRunes(k.Key2, n)}
+		if od.opt.Checksum {
+			k = withChecksums(k)
+		}
+		return k, true
+	}
+	if input != "" {
+		return Keys{}, false
+	}
+
+	// The sentinel is a reserved string and not a key, and has no checksum.
+	s := od.opt.Sentinel
+	if od.opt.MaxKeyLen > 0 {
+		s = truncateRunes(s, od.opt.MaxKeyLen)
+	}
+	return Keys{s, s, s}, true
+}
 
-	// key1 loses numeric modifiers that denote phonetic modifiers.
-	key1 := regexKey1.ReplaceAllString(key2, "")
+// keyLimit returns the number of runes that keys are truncated to, which
+// leaves room for the checksum in Options.MaxKeyLen, or -1 for no limit.
+func (od *ODIphone) keyLimit() int {
+	switch {
+	case od.opt.MaxKeyLen <= 0:
+		return -1
+	case od.opt.Checksum:
+		return od.opt.MaxKeyLen - 1
+	}
+	return od.opt.MaxKeyLen
+}
+
+// encodeTo encodes a normalized input that has no fixed keys into dst, and
+// truncates the keys to Options.MaxKeyLen runes at phoneme boundaries.
+func (od *ODIphone) encodeTo(dst [3][]byte, input string) [3][]byte {
+	limit := od.keyLimit()
+
+	// key2 accounts for hard and modified sounds. Simple words are encoded
+	// with a direct scan of their runes. bs are the offsets of its phonemes.
+	var (
+		buf [32]int
+		bs  []int
+		ok  bool
+	)
+	if dst[2], bs, ok = od.appendSimpleKey(dst[2][:0], buf[:0], input); !ok {
+		dst[2], bs = od.appendProcessed(dst[2][:0], buf[:0], input)
+	}
+
+	// key1 loses numeric modifiers that denote phonetic modifiers and
+	// the inherent vowel.
+	dst[1] = appendKey(dst[1][:0], dst[2], bs, '7', 0, limit)
 
 	// key0 loses numeric modifiers that denote hard sounds, doubled sounds,
-	// and phonetic modifiers.
-	key0 := regexKey0.ReplaceAllString(key2, "")
+	// and phonetic modifiers. key2 is truncated in place once the others
+	// are derived from it.
+	in0 := od.key0Input(input)
+	if in0 == input {
+		dst[0] = appendKey(dst[0][:0], dst[2], bs, '0', od.initialAspiration(input, dst[2]), limit)
+	}
+	dst[2] = appendKey(dst[2][:0], dst[2], bs, 0, 0, limit)
+	if in0 != input {
+		dst[0], bs = od.appendProcessed(dst[0][:0], bs[:0], in0)
+		dst[0] = appendKey(dst[0][:0], dst[0], bs, '0', od.initialAspiration(in0, dst[0]), limit)
+	}
+
+	if od.opt.Checksum {
+		for i := range dst {
+			dst[i] = appendChecksum(dst[i])
+		}
+	}
+	return dst
+}
 
-	return key0, key1, key2
+// Keys holds the three phonetic keys of a word, from the broadest (Key0)
+// to the narrowest (Key2).
+type Keys struct {
+	Key0 string
+	Key1 string
+	Key2 string
 }
 
-func (od *ODIphone) process(input string) string {
-	// Remove all non-odia characters.
-	input = regexNonOdia.ReplaceAllString(strings.Trim(input, ""), "")
+// EncodeKeys is the same as Encode, but returns the keys as a Keys struct.
+func (od *ODIphone) EncodeKeys(input string) Keys {
+	return od.encodeNormalized(od.normalize(input))
+}
 
-	// All character replacements are grouped between { and } to maintain
-	// separatability till the final step.
+// EncodeFull returns the keys of a word along with a readable Roman
+// transliteration of it, eg: ଭ୍ରମର = bhramara, normalizing the word once.
+func (od *ODIphone) EncodeFull(input string) (Keys, string) {
+	input = od.normalize(input)
+	return od.encodeNormalized(input), strings.Join(roman.transliterate(input), "")
+}
 
-	// Replace and group modified compounds.
-	input = od.replaceModifiedGlyphs(input, compounds, od.modCompounds)
+// EncodeCount returns the keys of a word along with the number of its Odia
+// letters (vowels and consonants) that are mapped, normalizing the word
+// once, eg: for ranking length-normalized matches. Non-Odia characters,
+// unmapped characters, and the modifiers (vowel signs, halants etc.) are
+// not counted, eg: ଭ୍ରମରେ has 4 letters.
+//
+// The count is not a field of Keys, as callers compare Keys with == to match
+// words, which would then differ by their length, and build them with
+// unkeyed literals of the three keys (eg: for AddException), which an extra
+// field would break.
+func (od *ODIphone) EncodeCount(input string) (Keys, int) {
+	input = od.normalize(input)
 
-	// Replace and group unmodified compounds.
-	for k, v := range compounds {
-		input = strings.ReplaceAll(input, k, `{`+v+`}`)
+	n := 0
+	for _, r := range input {
+		if od.glyphCode(r) != "" {
+			n++
+		}
 	}
+	return od.encodeNormalized(input), n
+}
 
-	// Replace and group modified consonants and vowels.
-	input = od.replaceModifiedGlyphs(input, consonants, od.modConsonants)
-	input = od.replaceModifiedGlyphs(input, vowels, od.modVowels)
+// EncodeJSON returns the word and its keys as a compact JSON object, eg:
+// {"word":"ଘର","key0":"GHR","key1":"GHR","key2":"GHR"}, for logging.
+func (od *ODIphone) EncodeJSON(input string) string {
+	k := od.EncodeKeys(input)
+	b, _ := json.Marshal(struct {
+		Word string `json:"word"`
+		Key0 string `json:"key0"`
+		Key1 string `json:"key1"`
+		Key2 string `json:"key2"`
+	}{input, k.Key0, k.Key1, k.Key2})
+	return string(b)
+}
 
-	// Replace and group unmodified consonants.
-	for k, v := range consonants {
-		input = strings.ReplaceAll(input, k, `{`+v+`}`)
+// EncodeTo is the same as Encode, but writes the three keys into the
+// caller's byte slices in dst, reusing and growing them as needed. Once the
+// slices have grown to the size of the keys, it does not allocate, except
+// for words that are rewritten by normalization or encoding (eg: with a
+// glide or a geminate), which suits encoding many words in a loop.
+func (od *ODIphone) EncodeTo(dst *[3][]byte, input string) {
+	input = od.normalize(input)
+	if k, ok := od.fixedKeys(input); ok {
+		dst[0] = append(dst[0][:0], k.Key0...)
+		dst[1] = append(dst[1][:0], k.Key1...)
+		dst[2] = append(dst[2][:0], k.Key2...)
+		return
 	}
+	*dst = od.encodeTo(*dst, input)
+}
 
-	// Replace and group unmodified vowels.
-	for k, v := range vowels {
-		input = strings.ReplaceAll(input, k, `{`+v+`}`)
+// truncateRunes truncates s to at most n runes, or returns s as is if n is
+// -1.
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
 	}
+	return s
+}
 
-	// Replace all modifiers.
-	for k, v := range modifiers {
-		input = strings.ReplaceAll(input, k, v)
+// appendKey appends key2 to dst phoneme by phoneme, where bs are the offsets
+// of the phonemes in key2, without the digits from lo to 9 and the variant
+// markers and their letters, or as is if lo is 0. The first keep bytes of
+// key2 are kept as is. It stops before the first phoneme that would take the
+// key past limit runes, unless limit is -1. dst may be key2[:0], as the key
+// is never longer than key2.
+func appendKey(dst, key2 []byte, bs []int, lo byte, keep, limit int) []byte {
+	n, start := 0, 0
+	for p := 0; p <= len(bs); p++ {
+		end := len(key2)
+		if p < len(bs) {
+			end = bs[p]
+		}
+
+		m := len(dst)
+		for i := start; i < end; i++ {
+			switch c := key2[i]; {
+			case lo != 0 && c == variant:
+				i++
+			case lo == 0 || i < keep || c < lo || c > '9':
+				dst = append(dst, c)
+				if utf8.RuneStart(c) {
+					n++
+				}
+			}
+		}
+		if limit >= 0 && n > limit {
+			return dst[:m]
+		}
+		start = end
 	}
+	return dst
+}
 
-	// Remove non-alphanumeric characters (losing the bracket grouping).
-	return regexAlphaNum.ReplaceAllString(input, "")
+// initialAspiration returns the number of bytes of key2 up to the hard sound
+// of the aspiration of the glyph an input starts with, with
+// Options.InitialAspiration, or 0.
+func (od *ODIphone) initialAspiration(input string, key2 []byte) int {
+	if !od.opt.InitialAspiration {
+		return 0
+	}
+	for _, g := range od.aspirates {
+		if strings.HasPrefix(input, g) {
+			return bytes.IndexByte(key2, hardSound[0]) + 1
+		}
+	}
+	return 0
+}
+
+// EncodeRunes is the same as Encode, but returns the keys as rune slices
+// that can be packed into a compact index.
+func (od *ODIphone) EncodeRunes(input string) ([]rune, []rune, []rune) {
+	key0, key1, key2 := od.Encode(input)
+	return []rune(key0), []rune(key1), []rune(key2)
+}
+
+// TryEncode is the same as EncodeKeys, but in strict mode, returns an
+// *UnmappedError if the input has Odia characters that are not in any of the
+// phonetic tables and would otherwise be silently dropped.
+func (od *ODIphone) TryEncode(input string) (Keys, error) {
+	if od.opt.Strict {
+		if r := od.unmapped(od.normalize(input)); len(r) > 0 {
+			return Keys{}, &UnmappedError{Word: input, Runes: r}
+		}
+	}
+	return od.EncodeKeys(input), nil
+}
+
+// UnmappedRunes returns the unique Odia runes in a word that are not in any of
+// the phonetic tables and are dropped from its keys, in the order of their
+// appearance. It is the informational counterpart of strict mode.
+func (od *ODIphone) UnmappedRunes(word string) []rune {
+	return od.unmapped(od.normalize(word))
+}
+
+// unmapped returns the unique runes in a normalized input that are not in any
+// of the phonetic tables, in the order of their appearance.
+func (od *ODIphone) unmapped(input string) []rune {
+	var (
+		out  []rune
+		seen = make(map[rune]bool)
+	)
+	for _, r := range input {
+		if od.known[r] || seen[r] {
+			continue
+		}
+		seen[r] = true
+		out = append(out, r)
+	}
+	return out
+}
+
+// AddException registers hand-tuned keys for a word (eg: a proper noun or an
+// irregular word) that the algorithm gets wrong. Encode returns the keys
+// verbatim for the word instead of processing it. The word is normalized
+// the same way Encode normalizes its input. AddException should not be
+// called concurrently with encoding.
+func (od *ODIphone) AddException(word string, keys Keys) {
+	od.exceptions[od.normalize(word)] = keys
+}
+
+// SetCode replaces the code of a glyph (a vowel, consonant, compound, or
+// modifier) in the tables of the tokenizer, eg: SetCode("ଙ", "Ṅ"). The
+// package tables and other tokenizers are unaffected. SetCode should not be
+// called concurrently with encoding.
+func (od *ODIphone) SetCode(glyph, code string) error {
+	glyph = norm.NFC.String(glyph)
+	if code == "" || strings.ContainsAny(code, "{}") {
+		return fmt.Errorf("odiphone: invalid code %q for %q", code, glyph)
+	}
+	for _, m := range []map[string]string{od.vowels, od.consonants, od.compounds, od.modifiers} {
+		if _, ok := m[glyph]; ok {
+			m[glyph] = code
+			od.compile()
+			return nil
+		}
+	}
+	return fmt.Errorf("odiphone: %q is not in the phonetic tables", glyph)
+}
+
+// AddCompound adds a compound (a sequence of Odia glyphs that is encoded as a
+// whole, eg: a conjunct) with its code to the tables of the tokenizer, or
+// replaces the code of one, eg: AddCompound("ମ୍ର", "MR"). The package tables
+// and other tokenizers are unaffected. AddCompound should not be called
+// concurrently with encoding.
+func (od *ODIphone) AddCompound(glyphs, code string) error {
+	glyphs = norm.NFC.String(glyphs)
+	if code == "" || strings.ContainsAny(code, "{}") {
+		return fmt.Errorf("odiphone: invalid code %q for %q", code, glyphs)
+	}
+	if utf8.RuneCountInString(glyphs) < 2 || strings.IndexFunc(glyphs, isNonOdia) >= 0 {
+		return fmt.Errorf("odiphone: %q is not a sequence of Odia glyphs", glyphs)
+	}
+	od.compounds[glyphs] = code
+	od.compile()
+	return nil
+}
+
+// Options returns the options the tokenizer was created with, eg: to create a
+// variant with New(WithOptions(od.Options()), ...).
+func (od *ODIphone) Options() Options {
+	return od.opt
+}
+
+// MergeTables returns a new tokenizer with the options of the receiver and
+// the union of the tables and exceptions of the two, eg: for composing a
+// base encoder with one of domain-specific overrides. The receiver takes
+// precedence on conflicts: a glyph of other that is in any table of the
+// receiver, or an exception of other that is one of the receiver too, is
+// ignored. Neither tokenizer is affected.
+func (od *ODIphone) MergeTables(other *ODIphone) *ODIphone {
+	c := od.Clone()
+	for _, t := range []struct{ dst, src map[string]string }{
+		{c.vowels, other.vowels},
+		{c.consonants, other.consonants},
+		{c.compounds, other.compounds},
+		{c.modifiers, other.modifiers},
+	} {
+		for g, code := range t.src {
+			if !c.hasGlyph(g) {
+				t.dst[g] = code
+			}
+		}
+	}
+	for w, k := range other.exceptions {
+		if _, ok := c.exceptions[w]; !ok {
+			c.exceptions[w] = k
+		}
+	}
+	c.compile()
+	return c
+}
+
+// hasGlyph reports if a glyph is in any of the tables of the tokenizer.
+func (od *ODIphone) hasGlyph(g string) bool {
+	for _, m := range []map[string]string{od.vowels, od.consonants, od.compounds, od.modifiers} {
+		if _, ok := m[g]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns an independent copy of the tokenizer, with its tables and
+// exceptions, that can be modified (eg: with SetCode and AddException)
+// without affecting the original.
+func (od *ODIphone) Clone() *ODIphone {
+	c := *od
+	c.vowels = copyTable(od.vowels)
+	c.consonants = copyTable(od.consonants)
+	c.compounds = copyTable(od.compounds)
+	c.modifiers = copyTable(od.modifiers)
+	c.exceptions = make(map[string]Keys, len(od.exceptions))
+	for k, v := range od.exceptions {
+		c.exceptions[k] = v
+	}
+	return &c
+}
+
+// normalize converts the input to the NFC form and maps confusables as
+// configured, and removes all non-Odia characters from it and the modifiers
+// at the start of it (eg: an OCR'd leading anusvara) that have no preceding
+// glyph to modify.
+func (od *ODIphone) normalize(input string) string {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return ""
+	}
+	if od.opt.VisualOrder {
+		input = ReorderMatras(input)
+	}
+	if !od.opt.DisableNFC {
+		input = NFCNormalize(input)
+	}
+	if !od.opt.DisableConfusables {
+		input = FixConfusables(input)
+	}
+	if od.opt.OCRMatras {
+		input = ocrMatras(input)
+	}
+	input = StripNonOdia(input)
+
+	// Reduce the stacked rendering variants of conjuncts to a consonant,
+	// halant, and consonant once the joiners are removed above.
+	input = strings.ReplaceAll(input, overline, "")
+	if strings.Contains(input, doubleHalant) {
+		input = regexHalants.ReplaceAllString(input, string(halant))
+	}
+	if od.opt.HomorganicNasal && strings.ContainsRune(input, 'ଂ') {
+		input = anusvaras.Replace(input)
+	}
+
+	for i, r := range input {
+		if od.modifierCode(r) == "" {
+			return input[i:]
+		}
+	}
+	return ""
+}
+
+// The stages of normalization below are applied by the tokenizer as its
+// options configure, in the order ReorderMatras, NFCNormalize,
+// FixConfusables, and StripNonOdia. They are exported for callers who clean
+// their input with only some of them before encoding it, or for use
+// elsewhere.
+
+// StripNonOdia removes all the characters outside the Oriya script from s,
+// including spaces, punctuation, and the zero-width joiners.
+func StripNonOdia(s string) string {
+	if strings.IndexFunc(s, isNonOdia) < 0 {
+		return s
+	}
+	return regexNonOdia.ReplaceAllString(s, "")
+}
+
+// NFCNormalize converts s to the Unicode NFC form, in which the two-part
+// vowel signs are composed (eg: େ + ା = ୋ) and the nukta consonants ଡ଼ and
+// ଢ଼ are decomposed.
+func NFCNormalize(s string) string {
+	if isNFC(s) {
+		return s
+	}
+	return norm.NFC.String(s)
+}
+
+// isNFC reports if s is in the NFC form without allocating. Odia runes are
+// checked directly, as the quick check of the norm package is inconclusive
+// for the vowel signs ା, ୖ, and ୗ, which only compose after େ.
+func isNFC(s string) bool {
+	var prev rune
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+		case r >= oriyaBlock && r < oriyaBlock+0x80:
+			if r == '\u0b5c' || r == '\u0b5d' || prev == 'େ' && (r == 'ା' || r == 'ୖ' || r == 'ୗ') || prev == halant && r == nukta {
+				return false
+			}
+		default:
+			return norm.NFC.QuickSpanString(s) == len(s)
+		}
+		prev = r
+	}
+	return true
+}
+
+// FixConfusables replaces the codepoints and sequences in s that render the
+// same as an Odia glyph with that glyph, eg: the Devanagari candrabindu with
+// ଁ, and ଅ + ା with ଆ.
+func FixConfusables(s string) string {
+	return confusables.Replace(s)
+}
+
+// isNonOdia reports if a rune is outside the Oriya script.
+func isNonOdia(r rune) bool {
+	return !unicode.Is(unicode.Oriya, r)
+}
+
+// matras are the vowel signs of the independent vowels.
+var matras = map[rune]rune{
+	'ଆ': 'ା',
+	'ଇ': 'ି',
+	'ଈ': 'ୀ',
+	'ଉ': 'ୁ',
+	'ଊ': 'ୂ',
+	'ଋ': 'ୃ',
+	'ୠ': 'ୄ',
+	'ଏ': 'େ',
+	'ଐ': 'ୈ',
+	'ଓ': 'ୋ',
+	'ଔ': 'ୌ',
+}
+
+// rewritesKey0 reports if the options rewrite the input of key0.
+func (od *ODIphone) rewritesKey0() bool {
+	return od.opt.NasalClusters || od.opt.GlideElision
+}
+
+// key0Input rewrites a normalized input for encoding key0 by the options
+// that only affect key0.
+func (od *ODIphone) key0Input(input string) string {
+	if od.opt.NasalClusters && strings.ContainsRune(input, halant) {
+		input = nasalClusters.Replace(input)
+	}
+	if od.opt.GlideElision && (strings.ContainsRune(input, 'ୟ') || hasMedialVowel(input)) {
+		input = elideGlides(input)
+	}
+	return input
+}
+
+// preBaseMatras are the vowel signs that are rendered before their
+// consonant.
+const preBaseMatras = "େୈ"
+
+// ReorderMatras moves the pre-base vowel signs that precede a consonant (or
+// a conjunct) in visual order to after it, in logical order, eg: େକ = କେ,
+// and େକା = କୋ once composed by NFCNormalize, which is to be applied after
+// it.
+func ReorderMatras(input string) string {
+	if strings.IndexAny(input, preBaseMatras) < 0 {
+		return input
+	}
+
+	var (
+		b  strings.Builder
+		rs = []rune(input)
+	)
+	b.Grow(len(input))
+	for i := 0; i < len(rs); i++ {
+		if !strings.ContainsRune(preBaseMatras, rs[i]) || i+1 == len(rs) || !isConsonant(rs[i+1]) {
+			b.WriteRune(rs[i])
+			continue
+		}
+
+		// The conjunct of consonants joined by halants.
+		j := consonantEnd(rs, i+1)
+		for j+1 < len(rs) && rs[j] == halant && isConsonant(rs[j+1]) {
+			j = consonantEnd(rs, j+1)
+		}
+		b.WriteString(string(rs[i+1 : j]))
+		b.WriteRune(rs[i])
+		i = j - 1
+	}
+	return b.String()
+}
+
+// ocrMatras replaces the independent vowels that directly follow a consonant
+// (or its nukta) with their vowel signs.
+func ocrMatras(input string) string {
+	var (
+		b    strings.Builder
+		prev rune
+	)
+	b.Grow(len(input))
+	for _, r := range input {
+		if m, ok := matras[r]; ok && (isConsonant(prev) || prev == nukta) {
+			r = m
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+func isConsonant(r rune) bool {
+	_, ok := consonants[string(r)]
+	return ok
 }
 
-func (od *ODIphone) replaceModifiedGlyphs(input string, glyphs map[string]string, r *regexp.Regexp) string {
-	for _, matches := range r.FindAllStringSubmatch(input, -1) {
-		for _, m := range matches {
-			if rep, ok := glyphs[m]; ok {
-				input = strings.ReplaceAll(input, m, rep)
+// retroflexes are the retroflex consonants, with which the retroflex stops
+// cluster in native words.
+const retroflexes = "ଟଠଡଢଣଷ"
+
+// hasLoanwordFinal reports if a normalized input ends in a cluster of a
+// consonant that is not retroflex and ଟ or ଡ, which is typical of English
+// loanwords, eg: ପୋସ୍ଟ and କାର୍ଡ.
+func hasLoanwordFinal(input string) bool {
+	last, n := utf8.DecodeLastRuneInString(input)
+	if last != 'ଟ' && last != 'ଡ' {
+		return false
+	}
+	h, m := utf8.DecodeLastRuneInString(input[:len(input)-n])
+	if h != halant {
+		return false
+	}
+	c, _ := utf8.DecodeLastRuneInString(input[:len(input)-n-m])
+	return isConsonant(c) && !strings.ContainsRune(retroflexes, c)
+}
+
+// markInherentVowels inserts the inherent vowel code after every consonant
+// (and its nukta) in a normalized input that is not followed by a vowel sign
+// or halant. With schwaDeletion, a word-final consonant is not marked.
+func markInherentVowels(input string, schwaDeletion bool) string {
+	var (
+		b  strings.Builder
+		rs = []rune(input)
+	)
+	for i, r := range rs {
+		b.WriteRune(r)
+
+		// The end of a consonant is the consonant itself or its nukta.
+		end := isConsonant(r) && (i+1 == len(rs) || rs[i+1] != nukta)
+		if r == nukta && i > 0 && isConsonant(rs[i-1]) {
+			end = true
+		}
+		if !end {
+			continue
+		}
+
+		if i+1 == len(rs) {
+			if !schwaDeletion {
+				b.WriteString(inherentVowel)
 			}
+			continue
+		}
+		if next := rs[i+1]; next != halant && !strings.ContainsRune(vowelSigns, next) {
+			b.WriteString(inherentVowel)
 		}
 	}
+	return b.String()
+}
+
+// appendProcessed appends key2 of a normalized input to dst, and the offsets
+// in dst at which its phonemes start to bs.
+func (od *ODIphone) appendProcessed(dst []byte, bs []int, input string) ([]byte, []int) {
+	input = od.rewrite(input)
+
+	var buf [8]compoundMatch
+	return od.encodeGlyphs(dst, bs, input, od.matchCompounds(buf[:0], input))
+}
+
+// rewrite rewrites a normalized input for encoding by reordering and
+// inserting glyphs as pronounced. The rewrites are skipped when the input
+// has nothing for them to rewrite.
+func (od *ODIphone) rewrite(input string) string {
+	hasHalant := strings.ContainsRune(input, halant)
+	if hasHalant {
+		input = hConjuncts.Replace(input)
+	}
+	if hasMedialVowel(input) {
+		input = vowelGlides.Replace(input)
+	}
+	if od.opt.InherentVowel {
+		input = markInherentVowels(input, od.opt.SchwaDeletion || od.opt.LoanwordSchwaDeletion && hasLoanwordFinal(input))
+	}
+	switch {
+	case od.opt.HardSounds && hasHalant && hasGeminate(input):
+		input = hardGeminates.Replace(input)
+	case !od.opt.DisableGeminates && hasHalant && hasGeminate(input):
+		input = geminates.Replace(input)
+	}
 	return input
 }
+
+// MatchedCompounds returns the entries of the compounds table that are
+// matched in a word when it is encoded, in the order of their appearance,
+// eg: ଆକାଙ୍କ୍ଷା = [ଙ୍କ୍ଷ], for checking which compounds of custom tables
+// take effect. Exceptions are not encoded and have no matched compounds.
+func (od *ODIphone) MatchedCompounds(word string) []string {
+	input := od.normalize(word)
+	if _, ok := od.exceptions[input]; ok || input == "" {
+		return nil
+	}
+	input = od.rewrite(input)
+
+	var out []string
+	for _, m := range od.matchCompounds(nil, input) {
+		out = append(out, input[m.start:m.end])
+	}
+	return out
+}
+
+// hasGeminate reports if an input has a halant between two identical runes,
+// which may make a geminate.
+func hasGeminate(input string) bool {
+	var prev rune
+	for i, r := range input {
+		if r == halant {
+			if next, _ := utf8.DecodeRuneInString(input[i+utf8.RuneLen(halant):]); next == prev {
+				return true
+			}
+		}
+		prev = r
+	}
+	return false
+}
+
+// hasMedialVowel reports if an input has an independent vowel after its
+// first rune, which may take a glide.
+func hasMedialVowel(input string) bool {
+	for i, r := range input {
+		if i > 0 && isVowel(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeGlyphs appends the codes of the glyphs of a rewritten input to dst in
+// a single pass, and the offsets in dst at which they start to bs. ms are the
+// compounds matched in the input, which are encoded as a glyph, as are the
+// ya-phalas that palatalize the preceding consonant. The unmapped Odia
+// characters are dropped unless they are to be retained. Of the halants that
+// link the glyphs of a conjunct of three or more consonants, only the first
+// is kept, so that the conjunct is a single consonant run, eg: ନ୍ତ୍ର = N2TR
+// and not N2T2R.
+func (od *ODIphone) encodeGlyphs(dst []byte, bs []int, input string, ms []compoundMatch) ([]byte, []int) {
+	var (
+		// links is the number of halants linking the current run of glyphs.
+		links = 0
+
+		// afterGlyph and afterHalant are set if the previous glyph or
+		// modifier was a glyph or a halant.
+		afterGlyph, afterHalant bool
+	)
+	writeGlyph := func(code string) {
+		// A glyph that is not linked to the previous one starts afresh.
+		if !afterHalant {
+			links = 0
+		}
+		bs = append(bs, len(dst))
+		dst = append(dst, code...)
+		afterGlyph, afterHalant = true, false
+	}
+
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case len(ms) > 0 && ms[0].start == i:
+			writeGlyph(od.compounds[input[i:ms[0].end]])
+			i, ms = ms[0].end, ms[1:]
+			continue
+
+		case r == halant && od.isYaPhala(input[i+size:]):
+			writeGlyph(yaPhala)
+			_, n := utf8.DecodeRuneInString(input[i+size:])
+			i += size + n
+			continue
+
+		case od.glyphCode(r) != "":
+			writeGlyph(od.glyphCode(r))
+
+		case od.modifierCode(r) != "":
+			// A halant between two glyphs links them into a conjunct.
+			link := r == halant && afterGlyph && od.startsGlyph(input, i+size, ms)
+			if link {
+				links++
+			}
+			if !link || links == 1 {
+				dst = append(dst, od.modifierCode(r)...)
+			}
+			afterGlyph, afterHalant = false, r == halant
+
+		default:
+			if od.opt.KeepUnmapped || !unicode.Is(unicode.Oriya, r) {
+				dst = utf8.AppendRune(dst, r)
+			}
+			afterGlyph, afterHalant = false, false
+		}
+		i += size
+	}
+	return dst, bs
+}
+
+// startsGlyph reports if input[i:] of encodeGlyphs starts with a glyph: the
+// next of the compounds ms, a ya-phala, or a vowel or consonant.
+func (od *ODIphone) startsGlyph(input string, i int, ms []compoundMatch) bool {
+	if i >= len(input) {
+		return false
+	}
+	r, size := utf8.DecodeRuneInString(input[i:])
+	return (len(ms) > 0 && ms[0].start == i) || od.glyphCode(r) != "" || (r == halant && od.isYaPhala(input[i+size:]))
+}
+
+// isYaPhala reports if the rest of an input after a halant starts with ଯ or
+// ୟ, making a ya-phala.
+func (od *ODIphone) isYaPhala(rest string) bool {
+	r, _ := utf8.DecodeRuneInString(rest)
+	return r == 'ଯ' || r == 'ୟ'
+}
+
+// glyphCode returns the code of a vowel or consonant rune, if any.
+func (od *ODIphone) glyphCode(r rune) string {
+	if r < oriyaBlock || r >= oriyaBlock+0x80 {
+		return ""
+	}
+	return od.glyphs[r-oriyaBlock]
+}
+
+// modifierCode returns the code of a modifier rune, if any.
+func (od *ODIphone) modifierCode(r rune) string {
+	if r < oriyaBlock || r >= oriyaBlock+0x80 {
+		return ""
+	}
+	return od.mods[r-oriyaBlock]
+}
+
+// phonemes returns the key2 codes of the phonemes in a normalized input,
+// each with its modifier codes, eg: ଭ୍ରମରେ = [BH2 R M R3].
+func (od *ODIphone) phonemes(input string) []string {
+	key2, bs := od.appendProcessed(nil, nil, input)
+
+	var out []string
+	start := 0
+	for _, end := range append(bs, len(key2)) {
+		if end > start {
+			out = append(out, string(key2[start:end]))
+		}
+		start = end
+	}
+	return out
+}
+
+// compoundMatch is a compound matched at input[start:end].
+type compoundMatch struct {
+	start, end int
+}
+
+// matchCompounds appends the non-overlapping compounds in an input to ms in
+// the order of their appearance. Of compounds that overlap, the longest is
+// matched wherever it starts, and of those of the same length, the lexically
+// first, eg: ଙ୍କ୍ଷ over ଙ୍କ, so that the precedence does not depend on the
+// order of the table. The glyphs of the others that are not in it are
+// encoded on their own.
+func (od *ODIphone) matchCompounds(ms []compoundMatch, input string) []compoundMatch {
+	// owner is the compound (its index in compoundList + 1) matched at each
+	// byte of the input, and -1 for the rest of the bytes it covers. The
+	// compounds are matched in the order of their precedence, each one
+	// where it does not overlap the ones before it.
+	var (
+		buf   [256]int32
+		owner []int32
+	)
+	if len(input) <= len(buf) {
+		owner = buf[:len(input)]
+	} else {
+		owner = make([]int32, len(input))
+	}
+
+	found := false
+	for n, c := range od.compoundList {
+		for i := 0; i < len(input); {
+			j := strings.Index(input[i:], c)
+			if j < 0 {
+				break
+			}
+			start, end := i+j, i+j+len(c)
+			if !isFree(owner[start:end]) {
+				i = start + 1
+				continue
+			}
+			owner[start] = int32(n + 1)
+			for k := start + 1; k < end; k++ {
+				owner[k] = -1
+			}
+			found, i = true, end
+		}
+	}
+	if !found {
+		return ms
+	}
+
+	for i := 0; i < len(input); i++ {
+		if n := owner[i]; n > 0 {
+			c := od.compoundList[n-1]
+			ms = append(ms, compoundMatch{i, i + len(c)})
+			i += len(c) - 1
+		}
+	}
+	return ms
+}
+
+// isFree reports if none of the bytes are owned by a compound.
+func isFree(owner []int32) bool {
+	for _, n := range owner {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,14 @@
 package odiphone
 
 import (
+	"encoding/json"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
 )
 
 type testVal struct {
@@ -43,9 +48,9 @@ func TestODIPhone(t *testing.T) {
 		{
 			word: "ଭ୍ରମଣ",
 			expected: expected{
-				"BHRMNH",
-				"BH2RMNH",
-				"BH2RMNH",
+				"BHRMN",
+				"BH2RMN",
+				"BH2RMN:R",
 			},
 		},
 	}
@@ -56,3 +61,999 @@ func TestODIPhone(t *testing.T) {
 		require.Equal(t, v.expected.val3, out3)
 	}
 }
+
+func TestVowelAI(t *testing.T) {
+	// ଐ is the diphthong "ai" (as in ଐରାବତ, airābata), not "ei".
+	phone := New()
+	out1, out2, out3 := phone.Encode("ଐରାବତ")
+	require.Equal(t, "AIRBT", out1)
+	require.Equal(t, "AIR1BT", out2)
+	require.Equal(t, "AIR1BT", out3)
+}
+
+func TestAddException(t *testing.T) {
+	phone := New()
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2RMR"}, phone.EncodeKeys("ଭ୍ରମର"))
+
+	exc := Keys{"BMR", "BMR", "BMR"}
+	phone.AddException("ଭ୍ରମର", exc)
+	require.Equal(t, exc, phone.EncodeKeys("ଭ୍ରମର"))
+
+	// Matched after normalization.
+	require.Equal(t, exc, phone.EncodeKeys(" ଭ୍ରମର!"))
+
+	// Other words are unaffected.
+	require.Equal(t, Keys{"BHRMR", "BH2RMR3", "BH2RMR3"}, phone.EncodeKeys("ଭ୍ରମରେ"))
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2RMR"}, New().EncodeKeys("ଭ୍ରମର"))
+}
+
+func TestCompoundKSSA(t *testing.T) {
+	phone := New()
+	require.Equal(t, Keys{"LKH", "LKH", "LKH:S"}, phone.EncodeKeys("ଲକ୍ଷ"))
+	require.Equal(t, Keys{"RKH", "RKH1", "RKH:S1"}, phone.EncodeKeys("ରକ୍ଷା"))
+	require.Equal(t, Keys{"PKH", "PKH5", "PKH:S5"}, phone.EncodeKeys("ପକ୍ଷୀ"))
+
+	// Colloquial spellings with ଖ match at key0 and key1, but not key2.
+	require.Equal(t, MatchKey1, phone.Compare("ଲକ୍ଷ", "ଲଖ"))
+	require.Equal(t, MatchKey1, phone.Compare("ରକ୍ଷା", "ରଖା"))
+}
+
+func TestStrict(t *testing.T) {
+	// ୰ (isshar) is in the Oriya block, but not in any table.
+	const word = "ଭ୍ରମ୰ର"
+
+	// Unmapped characters are dropped by default.
+	k, err := New().TryEncode(word)
+	require.NoError(t, err)
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2RMR"}, k)
+
+	o := DefaultOptions()
+	o.Strict = true
+	phone := NewWithOptions(o)
+	_, err = phone.TryEncode(word)
+	var uerr *UnmappedError
+	require.ErrorAs(t, err, &uerr)
+	require.Equal(t, []rune{'୰'}, uerr.Runes)
+	require.Equal(t, word, uerr.Word)
+	require.Contains(t, err.Error(), "U+0B70")
+
+	k, err = phone.TryEncode("ଭ୍ରମର")
+	require.NoError(t, err)
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2RMR"}, k)
+}
+
+func TestLeadingModifiers(t *testing.T) {
+	phone := New()
+	exp := phone.EncodeKeys("ଅଂଶ")
+	require.Equal(t, exp, phone.EncodeKeys("ଂଅଂଶ"))
+	require.Equal(t, exp, phone.EncodeKeys("ଂଁ ଅଂଶ"))
+	require.Equal(t, Keys{"SH", "SH", "SH"}, phone.EncodeKeys("ଂଶ"))
+	require.Equal(t, Keys{}, phone.EncodeKeys("ଂ"))
+}
+
+func TestEmptyInput(t *testing.T) {
+	phone := New()
+	for _, w := range []string{"", " ", " \t\n "} {
+		k0, k1, k2 := phone.Encode(w)
+		require.Equal(t, [3]string{}, [3]string{k0, k1, k2}, w)
+		require.Equal(t, Keys{}, phone.EncodeKeys(w), w)
+
+		// Empty inputs return early without running the pipeline.
+		require.Zero(t, testing.AllocsPerRun(100, func() { phone.Encode(w) }), w)
+
+		dst := [3][]byte{[]byte("A"), []byte("B"), []byte("C")}
+		phone.EncodeTo(&dst, w)
+		require.Equal(t, [3][]byte{{}, {}, {}}, dst, w)
+	}
+}
+
+func TestSingleVowelWords(t *testing.T) {
+	// Interjections and particles of a single independent vowel are encoded
+	// to the vowel's code by every encoder and option, without its variant
+	// in key0 and key1.
+	encoders := []*ODIphone{New(), New(WithInherentVowel(), WithSchwaDeletion()), New(WithMaxKeyLen(3)), New(WithStrict())}
+	for v, code := range vowels {
+		broad := code
+		if i := strings.IndexByte(code, variant); i >= 0 {
+			broad = code[:i]
+		}
+		for _, phone := range encoders {
+			require.Equal(t, Keys{broad, broad, code}, phone.EncodeKeys(v), v)
+			require.Equal(t, Keys{broad, broad, code}, phone.EncodeKeys(" "+v+" "), v)
+
+			var dst [3][]byte
+			phone.EncodeTo(&dst, v)
+			require.Equal(t, code, string(dst[2]), v)
+		}
+	}
+	require.Equal(t, Keys{"AA", "AA", "AA7"}, New().EncodeKeys("ଆଃ"))
+
+	// A lone vowel sign is an orphaned modifier and has no keys.
+	for _, m := range []string{"ା", "ି", "େ", "ୌ"} {
+		require.Equal(t, Keys{}, New().EncodeKeys(m), m)
+	}
+}
+
+func TestEncodeRunes(t *testing.T) {
+	phone := New()
+	for _, w := range []string{"ଅଂଶ", "ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ", ""} {
+		k0, k1, k2 := phone.Encode(w)
+		r0, r1, r2 := phone.EncodeRunes(w)
+		require.Equal(t, k0, string(r0))
+		require.Equal(t, k1, string(r1))
+		require.Equal(t, k2, string(r2))
+	}
+}
+
+func TestYaPhala(t *testing.T) {
+	phone := New()
+	require.Equal(t, Keys{"BDY", "B5DY21", "B5DY21"}, phone.EncodeKeys("ବିଦ୍ୟା"))
+	require.Equal(t, Keys{"STY", "STY2", "STY2"}, phone.EncodeKeys("ସତ୍ୟ"))
+	require.Equal(t, Keys{"LKHY", "LKHY2", "LKH:SY2"}, phone.EncodeKeys("ଲକ୍ଷ୍ୟ"))
+
+	// Ya-phala spelt with ଯ is the same.
+	require.Equal(t, MatchKey2, phone.Compare("ବିଦ୍ୟା", "ବିଦ୍ଯା"))
+
+	// Distinct from a plain ୟ.
+	require.Equal(t, MatchKey0, phone.Compare("ଦ୍ୟା", "ଦୟା"))
+}
+
+func TestKeepUnmapped(t *testing.T) {
+	// ୰ (isshar) is in the Oriya block, but not in any table.
+	const word = "ଭ୍ରମ୰ର"
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2RMR"}, New().EncodeKeys(word))
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2RMR"}, NewWithOptions(Options{}).EncodeKeys(word))
+
+	phone := New(WithKeepUnmapped())
+	require.Equal(t, Keys{"BHRM୰R", "BH2RM୰R", "BH2RM୰R"}, phone.EncodeKeys(word))
+	require.Equal(t, Keys{"BHRMR", "BH2RMR3", "BH2RMR3"}, phone.EncodeKeys("ଭ୍ରମରେ"))
+}
+
+func TestInherentVowel(t *testing.T) {
+	require.Equal(t, Keys{"GHR", "GHR", "GHR"}, New().EncodeKeys("ଘର"))
+
+	o := DefaultOptions()
+	o.InherentVowel = true
+	phone := NewWithOptions(o)
+	require.Equal(t, Keys{"GHR", "GHR", "GH9R9"}, phone.EncodeKeys("ଘର"))
+	require.Equal(t, Keys{"GHR", "GHR3", "GH9R3"}, phone.EncodeKeys("ଘରେ"))
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2R9M9R9"}, phone.EncodeKeys("ଭ୍ରମର"))
+	require.Equal(t, Keys{"A", "A", "A7"}, phone.EncodeKeys("ଅଂ"))
+	require.Equal(t, Keys{"KR", "KR", "K97R9"}, phone.EncodeKeys("କଂର"))
+
+	// Word-final schwa is deleted, but medial schwa is retained.
+	o.SchwaDeletion = true
+	phone = NewWithOptions(o)
+	require.Equal(t, Keys{"GHR", "GHR", "GH9R"}, phone.EncodeKeys("ଘର"))
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2R9M9R"}, phone.EncodeKeys("ଭ୍ରମର"))
+	require.Equal(t, Keys{"GHR", "GHR3", "GH9R3"}, phone.EncodeKeys("ଘରେ"))
+	require.Equal(t, MatchKey0, phone.Compare("ଘର", "ଘର୍"))
+}
+
+func TestCodeCharacters(t *testing.T) {
+	// Codes with non-alphanumeric characters (eg: IAST) are not stripped.
+	orig := consonants["ଟ"]
+	consonants["ଟ"] = "Ṭ"
+	defer func() { consonants["ଟ"] = orig }()
+
+	phone := New()
+	require.Equal(t, Keys{"ṬK", "ṬK1", "ṬK1"}, phone.EncodeKeys("ଟକା"))
+	require.Equal(t, []string{"Ṭ", "K1"}, phone.phonemes("ଟକା"))
+}
+
+func TestVelarNasal(t *testing.T) {
+	require.Equal(t, Keys{"WNK", "WNK1", "WNK1"}, New().EncodeKeys("ଙକା"))
+
+	for _, nasal := range []string{"WN", "Ṅ", "NG"} {
+		o := DefaultOptions()
+		o.VelarNasal = nasal
+		phone := NewWithOptions(o)
+		require.Equal(t, Keys{nasal + "K", nasal + "K1", nasal + "K1"}, phone.EncodeKeys("ଙକା"))
+
+		// The ଙ of a compound is pronounced ନ whatever its code, so the
+		// compounds are unaffected and do not collide with it.
+		require.Equal(t, Keys{"SHNKR", "SHN2KR", "SHN2KR"}, phone.EncodeKeys("ଶଙ୍କର"))
+		require.Equal(t, Keys{"GNG", "GN2G1", "GN2G1"}, phone.EncodeKeys("ଗଙ୍ଗା"))
+		require.Equal(t, phone.EncodeKeys("ଗନ୍ଗା"), phone.EncodeKeys("ଗଙ୍ଗା"))
+		require.NotEqual(t, phone.EncodeKeys("ଙ"), phone.EncodeKeys("ଙ୍ଗ"))
+	}
+
+	// A code that is the concatenation of other codes collides with them,
+	// and is rejected by Validate, eg: NG is ନଗ.
+	o := DefaultOptions()
+	o.VelarNasal = "NG"
+	phone := NewWithOptions(o)
+	require.Equal(t, phone.EncodeKeys("ନଗ"), phone.EncodeKeys("ଙ"))
+	require.EqualError(t, phone.Validate(), `odiphone: colliding codes: consonants: ଙ = "NG" is ନ + "G"`)
+	o.VelarNasal = "Ṅ"
+	require.NoError(t, NewWithOptions(o).Validate())
+	require.NoError(t, New().Validate())
+
+	// The package table is unaffected.
+	require.Equal(t, "WN", consonants["ଙ"])
+}
+
+func TestEncodeFull(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		word, roman string
+	}{
+		{"ଭ୍ରମର", "bhramara"},
+		{"ଭ୍ରମରେ", "bhramare"},
+		{"ଅଂଶ", "ansha"},
+		{"ରକ୍ଷା", "rakshaa"},
+		{"ବଡ଼", "bara"},
+		{"ଚାଁଦ", "chaanda"},
+		{"", ""},
+	}
+	for _, v := range tests {
+		k, r := phone.EncodeFull(v.word)
+		require.Equal(t, phone.EncodeKeys(v.word), k, v.word)
+		require.Equal(t, v.roman, r, v.word)
+	}
+}
+
+func TestEncodeCount(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		word string
+		n    int
+	}{
+		{"ଭ୍ରମର", 4},
+		{"ଭ୍ରମରେ", 4},
+		{"ଅଂଶ", 2},
+		{"ଲକ୍ଷ୍ୟ", 4},
+		{"ବଡ଼", 2},
+		{"ଆ", 1},
+		// Non-Odia and unmapped characters are not counted.
+		{"hello ଘର!", 2},
+		{"ଭ୍ରମ୰ର", 4},
+		{"ା", 0},
+		{"", 0},
+	}
+	for _, v := range tests {
+		k, n := phone.EncodeCount(v.word)
+		require.Equal(t, phone.EncodeKeys(v.word), k, v.word)
+		require.Equal(t, v.n, n, v.word)
+	}
+}
+
+func TestMultiHalantConjuncts(t *testing.T) {
+	phone := New()
+	require.Equal(t, Keys{"MNTR", "MN2TR", "MN2TR"}, phone.EncodeKeys("ମନ୍ତ୍ର"))
+	require.Equal(t, Keys{"ASTR", "AS2TR", "AS2TR"}, phone.EncodeKeys("ଅସ୍ତ୍ର"))
+	require.Equal(t, Keys{"STR", "S2TR5", "S2TR5"}, phone.EncodeKeys("ସ୍ତ୍ରୀ"))
+
+	// Two consonant conjuncts and separate conjuncts are unaffected.
+	require.Equal(t, Keys{"BHRMR", "BH2RMR", "BH2RMR"}, phone.EncodeKeys("ଭ୍ରମର"))
+	require.Equal(t, Keys{"STMR", "S2TM2R", "S2TM2R"}, phone.EncodeKeys("ସ୍ତମ୍ର"))
+	require.Equal(t, "MN2TRY2", phone.EncodeKeys("ମନ୍ତ୍ର୍ୟ").Key2)
+}
+
+func TestSplitAspiration(t *testing.T) {
+	o := DefaultOptions()
+	o.SplitAspiration = true
+	phone := NewWithOptions(o)
+
+	tests := []struct {
+		word string
+		keys Keys
+	}{
+		{"ଖ", Keys{"Kʰ", "Kʰ", "Kʰ"}},
+		{"ଘ", Keys{"Gʰ", "Gʰ", "Gʰ"}},
+		{"ଥ", Keys{"Tʰ", "Tʰ", "Tʰ"}},
+		{"ଠ", Keys{"Tʰ", "Tʰ", "Tʰ:R"}},
+		{"ଛ", Keys{"CHʰ", "CHʰ", "CHʰ"}},
+		{"ଥାଳି", Keys{"TʰL", "Tʰ1L5", "Tʰ1L:R5"}},
+		{"ଲକ୍ଷ", Keys{"LKʰ", "LKʰ", "LKʰ:S"}},
+		{"ଭ୍ରମର", Keys{"BʰRMR", "Bʰ2RMR", "Bʰ2RMR"}},
+		// Not confused with the consonant ହ.
+		{"କହ", Keys{"KHH", "KHH", "KHH"}},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.keys, phone.EncodeKeys(v.word), v.word)
+	}
+
+	// The aspirated consonant aligns with its unaspirated base.
+	require.Equal(t, 1, KeyDistance(phone.EncodeKeys("ଖାଲି").Key2, phone.EncodeKeys("କାଲି").Key2))
+
+	// The default is unaffected.
+	require.Equal(t, Keys{"KH", "KH", "KH"}, New().EncodeKeys("ଖ"))
+}
+
+func TestDentalRetroflex(t *testing.T) {
+	// The retroflexes are variants of the dentals, so minimal pairs are only
+	// told apart by key2.
+	phone := New()
+	for _, p := range [][2]string{{"ତାଳ", "ଟାଳ"}, {"ଥାଳ", "ଠାଳ"}, {"ତୋକ", "ଟୋକ"}, {"ଦାଳି", "ଡାଳି"}, {"ପାନି", "ପାଣି"}} {
+		require.Equal(t, MatchKey1, phone.Compare(p[0], p[1]), p)
+	}
+	require.Equal(t, Keys{"TL", "T1L", "T1L:R"}, phone.EncodeKeys("ତାଳ"))
+	require.Equal(t, Keys{"TL", "T1L", "T:R1L:R"}, phone.EncodeKeys("ଟାଳ"))
+
+	// A retroflex is not a sequence of dentals.
+	require.Equal(t, Keys{"T", "T", "T:R"}, phone.EncodeKeys("ଟ"))
+	require.Equal(t, Keys{"TT", "TT", "TT"}, phone.EncodeKeys("ତତ"))
+	require.Equal(t, MatchNone, phone.Compare("ଟ", "ତତ"))
+	require.Equal(t, MatchNone, phone.Compare("ଠ", "ତଥ"))
+}
+
+func TestLabialVariants(t *testing.T) {
+	phone := New()
+	require.Equal(t, Keys{"SBR", "S2BR", "S2B:WR"}, phone.EncodeKeys("ସ୍ୱର"))
+
+	// ବ, ୱ and ଵ match at key0 and key1, but not at key2.
+	require.Equal(t, MatchKey1, phone.Compare("ସ୍ୱର", "ସ୍ଵର"))
+	require.Equal(t, MatchKey1, phone.Compare("ସ୍ୱର", "ସ୍ବର"))
+	require.Equal(t, MatchKey1, phone.Compare("ଦ୍ୱାରା", "ଦ୍ବାରା"))
+	require.Equal(t, MatchKey1, phone.Compare("ଵନ", "ବନ"))
+}
+
+func TestEncodeTo(t *testing.T) {
+	phone := New()
+	phone.AddException("ଲଖ", Keys{"X", "Y", "Z"})
+
+	var dst [3][]byte
+	for _, w := range []string{"ଭ୍ରମରେ", "ଅଂଶ", "ଲକ୍ଷ୍ୟ", "ସ୍ୱର", "ଲଖ", ""} {
+		phone.EncodeTo(&dst, w)
+		k0, k1, k2 := phone.Encode(w)
+		require.Equal(t, k0, string(dst[0]), w)
+		require.Equal(t, k1, string(dst[1]), w)
+		require.Equal(t, k2, string(dst[2]), w)
+	}
+
+	// Words that are not rewritten are encoded without allocating once the
+	// slices have grown.
+	for _, w := range []string{"ଭ୍ରମରେ", "ଘର", "ଭାଷା", "ବିଦ୍ୟା", "ଲକ୍ଷ୍ୟ", "ସ୍ୱର", "ଆକାଙ୍କ୍ଷା", "ପ୍ରଧାନମନ୍ତ୍ରୀ", "ଲଖ"} {
+		phone.EncodeTo(&dst, w)
+		require.Zero(t, testing.AllocsPerRun(100, func() { phone.EncodeTo(&dst, w) }), w)
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	phone := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		phone.Encode("ଭ୍ରମରେ")
+	}
+}
+
+func BenchmarkEncodeTo(b *testing.B) {
+	var (
+		phone = New()
+		dst   [3][]byte
+	)
+	phone.EncodeTo(&dst, "ଭ୍ରମରେ")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		phone.EncodeTo(&dst, "ଭ୍ରମରେ")
+	}
+}
+
+func BenchmarkEncodeConjuncts(b *testing.B) {
+	var (
+		phone = New()
+		words = []string{"ମନ୍ତ୍ର", "ଲକ୍ଷ୍ୟ", "ଆକାଙ୍କ୍ଷା", "ବ୍ରାହ୍ମଣ", "ସ୍ୱର", "ବିଦ୍ୟା", "କୃଷ୍ଣ"}
+	)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		phone.Encode(words[i%len(words)])
+	}
+}
+
+func TestCompoundModifiers(t *testing.T) {
+	// The modifier codes of a compound immediately follow the compound's code.
+	phone := New()
+	tests := []struct {
+		word     string
+		keys     Keys
+		phonemes []string
+	}{
+		{"ଶଙ୍କା", Keys{"SHNK", "SHN2K1", "SHN2K1"}, []string{"SH", "N2K1"}},
+		{"ଅଙ୍କେ", Keys{"ANK", "AN2K3", "AN2K3"}, []string{"A", "N2K3"}},
+		{"ପଞ୍ଜି", Keys{"PNJ", "PN2J5", "PN2J5"}, []string{"P", "N2J5"}},
+		{"ଶଙ୍କାଙ୍କ", Keys{"SHNKNK", "SHN2K1N2K", "SHN2K1N2K"}, []string{"SH", "N2K1", "N2K"}},
+		{"ଶଙ୍କାଂ", Keys{"SHNK", "SHN2K1", "SHN2K17"}, []string{"SH", "N2K17"}},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.keys, phone.EncodeKeys(v.word), v.word)
+		require.Equal(t, v.phonemes, phone.phonemes(phone.normalize(v.word)), v.word)
+	}
+}
+
+func TestCompoundTwoPartMatras(t *testing.T) {
+	// A compound followed by a two-part vowel sign typed as its parts is the
+	// compound with the composed sign.
+	tests := []struct {
+		decomposed, composed string
+		phonemes             []string
+	}{
+		{"କ୍ଷ\u0b47\u0b3eଭ", "କ୍ଷୋଭ", []string{"KH:S4", "BH"}},
+		{"ଶ୍ର\u0b47\u0b3eତା", "ଶ୍ରୋତା", []string{"S:H2R4", "T1"}},
+		{"ଙ୍କ\u0b47\u0b57", "ଙ୍କୌ", []string{"N2K4"}},
+	}
+	phone := New()
+	for _, v := range tests {
+		require.Equal(t, phone.EncodeKeys(v.composed), phone.EncodeKeys(v.decomposed), v.composed)
+		require.Equal(t, v.phonemes, phone.phonemes(phone.normalize(v.decomposed)), v.composed)
+	}
+
+	// Without NFC, the parts are not composed, but their codes still follow
+	// the compound's code in its phoneme.
+	o := DefaultOptions()
+	o.DisableNFC = true
+	phone = NewWithOptions(o)
+	require.Equal(t, []string{"KH:S31", "BH"}, phone.phonemes(phone.normalize(tests[0].decomposed)))
+	require.Equal(t, []string{"N2K33"}, phone.phonemes(phone.normalize(tests[2].decomposed)))
+}
+
+func TestHConjuncts(t *testing.T) {
+	phone := New()
+	require.Equal(t, Keys{"BRMHHN", "B2R1M2HHN", "B2R1M2HHN:R"}, phone.EncodeKeys("ବ୍ରାହ୍ମଣ"))
+	require.Equal(t, Keys{"CHNHH", "CH5N2HH", "CH5N2HH"}, phone.EncodeKeys("ଚିହ୍ନ"))
+
+	// The h is pronounced after the consonant, as it is commonly misspelt.
+	require.Equal(t, MatchKey2, phone.Compare("ଚିହ୍ନ", "ଚିନ୍ହ"))
+	require.Equal(t, MatchKey2, phone.Compare("ବ୍ରାହ୍ମଣ", "ବ୍ରାମ୍ହଣ"))
+
+	// Not confused with ଣ, which is a variant of ନ.
+	require.Equal(t, MatchNone, phone.Compare("ଚିହ୍ନ", "ଚିଣ"))
+}
+
+func TestAdversarialInput(t *testing.T) {
+	// A long input of overlapping modified glyphs is encoded in linear time.
+	phone := New()
+	k := phone.EncodeKeys(strings.Repeat("କାଖିଗୁଙ୍କା", 20000))
+	require.Equal(t, strings.Repeat("K1KH5G6N2K1", 20000), k.Key2)
+	require.Less(t, scaling(phone, "କାଖିଗୁଙ୍କା", 5000), 8.0)
+}
+
+// scaling returns the ratio of the times it takes to encode 4n and n
+// repetitions of s, which is about 4 if encoding is linear and 16 if it is
+// quadratic. The fastest of a few runs of each, after a garbage collection,
+// is taken to reduce noise.
+func scaling(od *ODIphone, s string, n int) float64 {
+	fastest := func(w string) time.Duration {
+		var min time.Duration
+		for i := 0; i < 5; i++ {
+			runtime.GC()
+			start := time.Now()
+			od.EncodeKeys(w)
+			if d := time.Since(start); i == 0 || d < min {
+				min = d
+			}
+		}
+		return min
+	}
+	return float64(fastest(strings.Repeat(s, 4*n))) / float64(fastest(strings.Repeat(s, n)))
+}
+
+func TestLongConjunctChains(t *testing.T) {
+	// A long chain of consonants joined by halants is encoded in a single
+	// pass in linear time, with every option that rewrites conjuncts.
+	phone := New()
+	k := phone.EncodeKeys(strings.Repeat("କ୍", 100000) + "କ")
+	require.Equal(t, strings.Repeat("KK2", 50000)+"K", k.Key2)
+	require.Less(t, scaling(phone, "କ୍", 25000), 8.0)
+
+	phone = New(WithVisualOrder(), WithInherentVowel(), WithHomorganicNasal(), WithNasalClusters())
+	for _, s := range []string{"କ୍", "େକ୍ଷ୍", "ନ୍ତ୍ର୍"} {
+		require.NotEmpty(t, phone.EncodeKeys(strings.Repeat(s, 50000)).Key0, s)
+		require.Less(t, scaling(phone, s, 12500), 8.0, s)
+	}
+}
+
+func TestVowelGlides(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		word string
+		keys Keys
+	}{
+		{"ନୂଆ", Keys{"NB", "N6B1", "N6B:W1"}},
+		{"ଦିଆ", Keys{"DY", "D5Y1", "D5Y1"}},
+		{"ଉଆସ", Keys{"UBS", "UB1S", "UB:W1S"}},
+		{"ପିଅ", Keys{"PY", "P5Y", "P5Y"}},
+		// Not a glide.
+		{"ଆଇନ", Keys{"AAIN", "AAIN", "AAIN"}},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.keys, phone.EncodeKeys(v.word), v.word)
+	}
+
+	// Spellings with and without the glide are the same.
+	require.Equal(t, MatchKey2, phone.Compare("ନୂଆ", "ନୂୱା"))
+	require.Equal(t, MatchKey2, phone.Compare("ଦିଆ", "ଦିୟା"))
+	require.Equal(t, MatchKey2, phone.Compare("ଉଆସ", "ଉୱାସ"))
+}
+
+func TestDialect(t *testing.T) {
+	newDialect := func(d Dialect) *ODIphone {
+		o := DefaultOptions()
+		o.Dialect = d
+		return NewWithOptions(o)
+	}
+	var (
+		std     = newDialect(DialectStandard)
+		coastal = newDialect(DialectCoastal)
+		western = newDialect(DialectWestern)
+	)
+
+	require.Equal(t, Keys{"SHL", "SH1L", "SH1L:R"}, std.EncodeKeys("ଶାଳ"))
+	require.Equal(t, Keys{"SL", "S1L", "S1L:R"}, coastal.EncodeKeys("ଶାଳ"))
+	require.Equal(t, Keys{"SL", "S1L", "S1L"}, western.EncodeKeys("ଶାଳ"))
+
+	require.Equal(t, Keys{"KN", "K1N", "K1N:R"}, coastal.EncodeKeys("କାଣ"))
+	require.Equal(t, Keys{"KN", "K1N", "K1N"}, western.EncodeKeys("କାଣ"))
+
+	// ଶ and ସ are the same in the coastal dialect, but not in standard Odia.
+	require.Equal(t, MatchNone, std.Compare("ଶାଳ", "ସାଳ"))
+	require.Equal(t, MatchKey2, coastal.Compare("ଶାଳ", "ସାଳ"))
+
+	// The default is standard.
+	require.Equal(t, std.EncodeKeys("ଶାଳ"), New().EncodeKeys("ଶାଳ"))
+
+	// Western deletes the word-final schwa.
+	o := DefaultOptions()
+	o.Dialect = DialectWestern
+	o.InherentVowel = true
+	require.Equal(t, "GH9R", NewWithOptions(o).EncodeKeys("ଘର").Key2)
+}
+
+func TestConfusables(t *testing.T) {
+	od := New()
+	for _, c := range []struct {
+		confusable, canonical string
+	}{
+		{"ଅାମ", "ଆମ"},
+		{"ଏୖରାବତ", "ଐରାବତ"},
+		{"ଓୗଷଧ", "ଔଷଧ"},
+		{"ଚନ्ଦ्ର", "ଚନ୍ଦ୍ର"},
+		{"ବंଶ", "ବଂଶ"},
+		{"ଦୁःଖ", "ଦୁଃଖ"},
+		{"ଚାँଦ", "ଚାଁଦ"},
+		{"ଡ़", "ଡ଼"},
+	} {
+		require.Equal(t, od.EncodeKeys(c.canonical), od.EncodeKeys(c.confusable), c.confusable)
+	}
+}
+
+func TestLongASpelling(t *testing.T) {
+	// ଅ + ା typed for ଆ is the long-a vowel and not ଅ with a vowel sign.
+	od := New()
+	for _, w := range [][2]string{{"ଅା", "ଆ"}, {"ଅାକାଶ", "ଆକାଶ"}, {"ଅାମେ", "ଆମେ"}, {"ନୂଅା", "ନୂଆ"}} {
+		require.Equal(t, od.EncodeKeys(w[1]), od.EncodeKeys(w[0]), w[0])
+
+		var dst [3][]byte
+		od.EncodeTo(&dst, w[0])
+		require.Equal(t, od.EncodeKeys(w[1]).Key2, string(dst[2]), w[0])
+	}
+	require.Equal(t, Keys{"AA", "AA", "AA"}, od.EncodeKeys("ଅା"))
+}
+
+func TestStripNonOdia(t *testing.T) {
+	require.Equal(t, "ଭ୍ରମର", StripNonOdia("ଭ୍ରମର"))
+	require.Equal(t, "ଭ୍ରମରଅଂଶ", StripNonOdia(" ଭ୍ରମର, ଅଂଶ! "))
+	require.Equal(t, "କ୍ଷ", StripNonOdia("କ୍\u200dଷ"))
+	require.Equal(t, "", StripNonOdia("hello"))
+}
+
+func TestNFCNormalize(t *testing.T) {
+	require.Equal(t, "\u0b4b", NFCNormalize("\u0b47\u0b3e"))
+	require.Equal(t, "\u0b4c", NFCNormalize("\u0b47\u0b57"))
+	require.Equal(t, "\u0b21\u0b3c", NFCNormalize("\u0b5c"))
+	require.Equal(t, "ଭ୍ରମର", NFCNormalize("ଭ୍ରମର"))
+
+	// Only the marks are composed, and not the confusables.
+	require.Equal(t, "ଅା", NFCNormalize("ଅା"))
+
+	// The direct check of normalized inputs agrees with the norm package on
+	// Odia inputs, and falls back to its quick check on others, which may
+	// take a normalized input for one to normalize, but not the reverse.
+	rs := []rune{'a', 'e'}
+	for r := rune(oriyaBlock); r < oriyaBlock+0x80; r++ {
+		rs = append(rs, r)
+	}
+	for _, a := range rs {
+		for _, b := range rs {
+			s := string([]rune{a, b})
+			require.Equal(t, norm.NFC.IsNormalString(s), isNFC(s), "%q", s)
+			for _, s := range []string{s + "\u0301", "\u0951" + s} {
+				require.True(t, !isNFC(s) || norm.NFC.IsNormalString(s), "%q", s)
+			}
+		}
+	}
+}
+
+func TestFixConfusables(t *testing.T) {
+	require.Equal(t, "ଆମ", FixConfusables("ଅାମ"))
+	require.Equal(t, "ବଂଶ", FixConfusables("ବंଶ"))
+	require.Equal(t, "ଚନ୍ଦ୍ର", FixConfusables("ଚନ्ଦ्ର"))
+	require.Equal(t, "ଭ୍ରମର", FixConfusables("ଭ୍ରମର"))
+
+	// The others are not removed.
+	require.Equal(t, "ଆମ hello", FixConfusables("ଅାମ hello"))
+}
+
+func TestReorderMatras(t *testing.T) {
+	require.Equal(t, "କେ", ReorderMatras("େକ"))
+	require.Equal(t, "କ୍ଷେତ", ReorderMatras("େକ୍ଷତ"))
+	require.Equal(t, "ଭ୍ରମରେ", ReorderMatras("ଭ୍ରମେର"))
+	require.Equal(t, "କୋ", NFCNormalize(ReorderMatras("େକା")))
+
+	// The signs not before a consonant are not moved.
+	require.Equal(t, "ଭ୍ରମରେ", ReorderMatras("ଭ୍ରମରେ"))
+	require.Equal(t, "େ", ReorderMatras("େ"))
+}
+
+func TestEncodeJSON(t *testing.T) {
+	od := New()
+	s := od.EncodeJSON("ଭ୍ରମରେ")
+	require.True(t, json.Valid([]byte(s)))
+	require.Equal(t, `{"word":"ଭ୍ରମରେ","key0":"BHRMR","key1":"BH2RMR3","key2":"BH2RMR3"}`, s)
+
+	var v map[string]string
+	require.NoError(t, json.Unmarshal([]byte(od.EncodeJSON(`"ଘର"`)), &v))
+	require.Equal(t, map[string]string{"word": `"ଘର"`, "key0": "GHR", "key1": "GHR", "key2": "GHR"}, v)
+}
+
+func TestUnmappedRunes(t *testing.T) {
+	od := New()
+	require.Empty(t, od.UnmappedRunes("ଭ୍ରମର"))
+	require.Empty(t, od.UnmappedRunes("hello"))
+	require.Equal(t, []rune{'୧', '୰'}, od.UnmappedRunes("ଘର୧୰୧ abc"))
+	require.Equal(t, od.EncodeKeys("ଘର"), od.EncodeKeys("ଘର୧୰୧"))
+}
+
+func TestFlaps(t *testing.T) {
+	od := New()
+	// ଡ଼ precomposed and as ଡ and a nukta.
+	for _, w := range []string{"ବ\u0b5c", "ବ\u0b21\u0b3c"} {
+		require.Equal(t, Keys{"BD", "BD", "BD:F"}, od.EncodeKeys(w))
+	}
+	require.Equal(t, Keys{"PDH", "PDH1", "PDH:F1"}, od.EncodeKeys("ପଢ଼ା"))
+	require.Equal(t, Keys{"GHD", "GH4D1", "GH4D:F1"}, od.EncodeKeys("ଘ\u0b4bଡ\u0b3cା"))
+
+	// The flaps match the stops without the nukta in key1, but not key2.
+	require.Equal(t, MatchKey1, od.Compare("ବଡ଼", "ବଡ"))
+	require.Equal(t, MatchKey1, od.Compare("ପଢ଼ା", "ପଢା"))
+}
+
+func TestVocalicR(t *testing.T) {
+	phone := New()
+	require.Equal(t, Keys{"KSHN", "K6SH2N", "K6:RSH2N:R"}, phone.EncodeKeys("କୃଷ୍ଣ"))
+	require.Equal(t, Keys{"KSHN", "K6SH2N", "K6SH2N:R"}, phone.EncodeKeys("କୁଷ୍ଣ"))
+
+	// ୃ differs from ୁ and ୂ at key2 only.
+	require.Equal(t, MatchKey1, phone.Compare("କୃଷ୍ଣ", "କୁଷ୍ଣ"))
+	require.Equal(t, MatchKey1, phone.Compare("ମୃତ", "ମୂତ"))
+	require.Equal(t, MatchKey2, phone.Compare("ମୁତ", "ମୂତ"))
+}
+
+func TestSentinel(t *testing.T) {
+	require.Equal(t, Keys{}, New().EncodeKeys("hello"))
+
+	phone := New(WithSentinel("∅"))
+	for _, w := range []string{"hello", "", " 123 ", "ଂ"} {
+		require.Equal(t, Keys{"∅", "∅", "∅"}, phone.EncodeKeys(w), w)
+
+		var dst [3][]byte
+		phone.EncodeTo(&dst, w)
+		require.Equal(t, [3][]byte{[]byte("∅"), []byte("∅"), []byte("∅")}, dst, w)
+	}
+
+	// Odia words, and Odia words among others, are unaffected.
+	require.Equal(t, Keys{"GHR", "GHR", "GHR"}, phone.EncodeKeys("ଘର"))
+	require.Equal(t, Keys{"GHR", "GHR", "GHR"}, phone.EncodeKeys("hello ଘର"))
+}
+
+func TestOverlappingCompounds(t *testing.T) {
+	phone := New()
+
+	// ଙ୍କ୍ଷ is taken as a whole and not as ଙ୍କ and a stray ଷ.
+	require.Equal(t, Keys{"AAKNKH", "AAK1N2KH1", "AAK1N2KH:S1"}, phone.EncodeKeys("ଆକାଙ୍କ୍ଷା"))
+	require.Equal(t, Keys{"SNKHP", "SN2KH3P", "SN2KH:S3P"}, phone.EncodeKeys("ସଙ୍କ୍ଷେପ"))
+
+	// ଙ୍କ followed by another conjunct leaves the rest intact.
+	require.Equal(t, Keys{"SHNKR", "SHN2K2R", "SHN2K2R"}, phone.EncodeKeys("ଶଙ୍କ୍ର"))
+
+	// The longest compound wins regardless of the order of the table.
+	for i := 0; i < 10; i++ {
+		od := New()
+		require.NoError(t, od.AddCompound("ଙ୍କ୍ର", "NKR"))
+		require.Equal(t, "SHNKR", od.EncodeKeys("ଶଙ୍କ୍ର").Key2)
+	}
+
+	// Of compounds that overlap, the longest wins wherever it starts, and
+	// the rest of the other is encoded on its own.
+	for i := 0; i < 10; i++ {
+		od := New()
+		require.NoError(t, od.AddCompound("କ୍ତ", "KT"))
+		require.NoError(t, od.AddCompound("ତ୍ରୀ", "TRI"))
+		require.Equal(t, "BH3K2TRI", od.EncodeKeys("ଭେକ୍ତ୍ରୀ").Key2)
+		require.Equal(t, "BH3TRI", od.EncodeKeys("ଭେତ୍ରୀ").Key2)
+		require.Equal(t, []string{"ତ୍ରୀ"}, od.MatchedCompounds("ଭେକ୍ତ୍ରୀ"))
+	}
+
+	// Of compounds of the same length, the lexically first wins wherever
+	// it starts (କ୍ତ < ତ୍ର < ର୍କ).
+	for i := 0; i < 10; i++ {
+		od := New()
+		require.NoError(t, od.AddCompound("କ୍ତ", "KT"))
+		require.NoError(t, od.AddCompound("ତ୍ର", "TR"))
+		require.NoError(t, od.AddCompound("ର୍କ", "RK"))
+		require.Equal(t, "ATR2K", od.EncodeKeys("ଅତ୍ର୍କ").Key2)
+		require.Equal(t, "AR2KT", od.EncodeKeys("ଅର୍କ୍ତ").Key2)
+		require.Equal(t, []string{"କ୍ତ"}, od.MatchedCompounds("ଅର୍କ୍ତ"))
+	}
+}
+
+func TestMatchedCompounds(t *testing.T) {
+	phone := New()
+	require.Equal(t, []string{"ଙ୍କ୍ଷ"}, phone.MatchedCompounds("ଆକାଙ୍କ୍ଷା"))
+	require.Equal(t, []string{"କ୍ଷ", "କ୍ଷ"}, phone.MatchedCompounds("ଲକ୍ଷକ୍ଷ"))
+	require.Equal(t, []string{"ଶ୍ର"}, phone.MatchedCompounds("ଶ୍ରୀ"))
+	require.Equal(t, []string{"\u0b21\u0b3c"}, phone.MatchedCompounds("ବ\u0b5c"))
+	require.Empty(t, phone.MatchedCompounds("ଭ୍ରମର"))
+	require.Empty(t, phone.MatchedCompounds(""))
+
+	// A custom compound is reported once it takes effect.
+	require.Empty(t, phone.MatchedCompounds("ଆମ୍ର"))
+	require.NoError(t, phone.AddCompound("ମ୍ର", "MR"))
+	require.Equal(t, []string{"ମ୍ର"}, phone.MatchedCompounds("ଆମ୍ର"))
+
+	// Exceptions are not encoded.
+	phone.AddException("ଲକ୍ଷ", Keys{"L", "L", "L"})
+	require.Empty(t, phone.MatchedCompounds("ଲକ୍ଷ"))
+}
+
+func TestGeminates(t *testing.T) {
+	phone := New()
+
+	// Geminates are doubled consonants, while clusters retain the halant.
+	require.Equal(t, Keys{"ANN", "ANN", "ANN"}, phone.EncodeKeys("ଅନ୍ନ"))
+	require.Equal(t, Keys{"STT", "STT1", "STT1"}, phone.EncodeKeys("ସତ୍ତା"))
+	require.Equal(t, Keys{"BLL", "B5LL1", "B5LL1"}, phone.EncodeKeys("ବିଲ୍ଲା"))
+	require.Equal(t, Keys{"ANT", "AN2T", "AN2T"}, phone.EncodeKeys("ଅନ୍ତ"))
+	require.Equal(t, MatchNone, phone.Compare("ଅନ୍ନ", "ଅନ୍ତ"))
+
+	// The first consonant of a geminate does not carry the inherent vowel.
+	require.Equal(t, "ANN9", New(WithInherentVowel()).EncodeKeys("ଅନ୍ନ").Key2)
+
+	// The halant as a cluster.
+	phone = New(WithoutGeminates())
+	require.Equal(t, Keys{"ANN", "AN2N", "AN2N"}, phone.EncodeKeys("ଅନ୍ନ"))
+}
+
+func TestClone(t *testing.T) {
+	phone := New(WithDialect(DialectCoastal))
+	phone.AddException("ଭ୍ରମର", Keys{"BMR", "BMR", "BMR"})
+
+	c := phone.Clone()
+	require.Equal(t, phone.Options(), c.Options())
+	for _, w := range []string{"ଭ୍ରମର", "ଶାଳ", "ଙକା"} {
+		require.Equal(t, phone.EncodeKeys(w), c.EncodeKeys(w), w)
+	}
+
+	// Changes to the clone do not affect the original, and vice versa.
+	require.NoError(t, c.SetCode("ଙ", "Ṅ"))
+	c.AddException("ଘର", Keys{"G", "G", "G"})
+	phone.AddException("ଘରେ", Keys{"G3", "G3", "G3"})
+
+	require.Equal(t, Keys{"ṄK", "ṄK1", "ṄK1"}, c.EncodeKeys("ଙକା"))
+	require.Equal(t, Keys{"WNK", "WNK1", "WNK1"}, phone.EncodeKeys("ଙକା"))
+	require.Equal(t, Keys{"G", "G", "G"}, c.EncodeKeys("ଘର"))
+	require.Equal(t, Keys{"GHR", "GHR", "GHR"}, phone.EncodeKeys("ଘର"))
+	require.Equal(t, Keys{"GHR", "GHR3", "GHR3"}, c.EncodeKeys("ଘରେ"))
+	require.Equal(t, Keys{"BMR", "BMR", "BMR"}, c.EncodeKeys("ଭ୍ରମର"))
+	require.Equal(t, "WN", consonants["ଙ"])
+
+	// A variant with different options.
+	v := New(WithOptions(phone.Options()), WithDialect(DialectStandard))
+	require.Equal(t, Keys{"SHL", "SH1L", "SH1L:R"}, v.EncodeKeys("ଶାଳ"))
+	require.Equal(t, Keys{"SL", "S1L", "S1L:R"}, phone.EncodeKeys("ଶାଳ"))
+}
+
+func TestMergeTables(t *testing.T) {
+	base := New()
+	require.NoError(t, base.SetCode("ଙ", "Ṅ"))
+	base.AddException("ଭ୍ରମର", Keys{"BMR", "BMR", "BMR"})
+
+	domain := New(WithHistoric())
+	require.NoError(t, domain.SetCode("ଙ", "Ŋ"))
+	require.NoError(t, domain.SetCode("ଳ", "LL"))
+	domain.AddException("ଭ୍ରମର", Keys{"X", "X", "X"})
+	domain.AddException("ଘର", Keys{"G", "G", "G"})
+
+	m := base.MergeTables(domain)
+
+	// The receiver's codes and exceptions take precedence on conflicts.
+	require.Equal(t, Keys{"ṄK", "ṄK1", "ṄK1"}, m.EncodeKeys("ଙକା"))
+	require.Equal(t, Keys{"BMR", "BMR", "BMR"}, m.EncodeKeys("ଭ୍ରମର"))
+	require.Equal(t, base.EncodeKeys("ଶାଳ"), m.EncodeKeys("ଶାଳ"))
+
+	// The glyphs and exceptions of the other are added.
+	require.Equal(t, Keys{"L", "L", "L:U"}, m.EncodeKeys("ଌ"))
+	require.Equal(t, Keys{"G", "G", "G"}, m.EncodeKeys("ଘର"))
+	require.Equal(t, base.Options(), m.Options())
+
+	// Neither is affected.
+	require.Equal(t, Keys{}, base.EncodeKeys("ଌ"))
+	require.Equal(t, Keys{"GHR", "GHR", "GHR"}, base.EncodeKeys("ଘର"))
+	require.Equal(t, Keys{"ŊK", "ŊK1", "ŊK1"}, domain.EncodeKeys("ଙକା"))
+
+	// The other way around.
+	m = domain.MergeTables(base)
+	require.Equal(t, Keys{"ŊK", "ŊK1", "ŊK1"}, m.EncodeKeys("ଙକା"))
+	require.Equal(t, Keys{"X", "X", "X"}, m.EncodeKeys("ଭ୍ରମର"))
+}
+
+func TestSetCode(t *testing.T) {
+	phone := New()
+	require.NoError(t, phone.SetCode("ୃ", "R6"))
+	require.Equal(t, "KR6SH2N:R", phone.EncodeKeys("କୃଷ୍ଣ").Key2)
+
+	// Normalized, so that decomposed glyphs are found.
+	require.NoError(t, phone.SetCode("ଡ଼", "R8"))
+	require.Equal(t, "BR8", phone.EncodeKeys("ବଡ଼").Key2)
+
+	require.Error(t, phone.SetCode("x", "X"))
+	require.Error(t, phone.SetCode("କ", ""))
+	require.Error(t, phone.SetCode("କ", "{K}"))
+	require.Equal(t, "K", phone.EncodeKeys("କ").Key2)
+}
+
+func TestAddCompound(t *testing.T) {
+	phone := New()
+	require.Equal(t, "AAM2R", phone.EncodeKeys("ଆମ୍ର").Key2)
+	require.NoError(t, phone.AddCompound("ମ୍ର", "MR"))
+	require.Equal(t, "AAMR", phone.EncodeKeys("ଆମ୍ର").Key2)
+	require.Equal(t, "MR1", phone.EncodeKeys("ମ୍ରା").Key2)
+
+	// An existing compound's code is replaced, and a decomposed one found.
+	require.NoError(t, phone.AddCompound("ଡ଼", "R"))
+	require.Equal(t, "BR", phone.EncodeKeys("ବଡ଼").Key2)
+
+	// The package table and other tokenizers are unaffected.
+	require.NotContains(t, compounds, "ମ୍ର")
+	require.Equal(t, "AAM2R", New().EncodeKeys("ଆମ୍ର").Key2)
+	require.Equal(t, "MR1", phone.Clone().EncodeKeys("ମ୍ରା").Key2)
+
+	require.Error(t, phone.AddCompound("ମ", "M"))
+	require.Error(t, phone.AddCompound("ମr", "M"))
+	require.Error(t, phone.AddCompound("ମ୍ର", ""))
+	require.Error(t, phone.AddCompound("ମ୍ର", "{M}"))
+}
+
+func TestOCRMatras(t *testing.T) {
+	phone := New(WithOCRMatras())
+	for _, c := range []struct {
+		ocr, word string
+	}{
+		{"ଭ୍ରମରଏ", "ଭ୍ରମରେ"},
+		{"କଆମ", "କାମ"},
+		{"ପଈଲଏ", "ପୀଲେ"},
+		{"ଘଓଡ଼ଆ", "ଘୋଡ଼ା"},
+		{"ଗଙ୍ଗଆ", "ଗଙ୍ଗା"},
+	} {
+		require.Equal(t, phone.EncodeKeys(c.word), phone.EncodeKeys(c.ocr), c.ocr)
+		require.NotEqual(t, New().EncodeKeys(c.word), New().EncodeKeys(c.ocr), c.ocr)
+	}
+
+	// Word-initial vowels, and vowels after vowel signs or a space, are
+	// unaffected.
+	require.Equal(t, New().EncodeKeys("ଆମେ"), phone.EncodeKeys("ଆମେ"))
+	require.Equal(t, New().EncodeKeys("ଭାଇ"), phone.EncodeKeys("ଭାଇ"))
+	require.Equal(t, New().EncodeKeys("ଦିଆ"), phone.EncodeKeys("ଦିଆ"))
+	require.Equal(t, New().EncodeKeys("ଘର ଆମ"), phone.EncodeKeys("ଘର ଆମ"))
+}
+
+func TestMaxKeyLen(t *testing.T) {
+	// ଭ୍ରମରେ = BH2 R M R3.
+	for _, c := range []struct {
+		n    int
+		keys Keys
+	}{
+		{0, Keys{"BHRMR", "BH2RMR3", "BH2RMR3"}},
+		{7, Keys{"BHRMR", "BH2RMR3", "BH2RMR3"}},
+		{6, Keys{"BHRMR", "BH2RM", "BH2RM"}},
+		{5, Keys{"BHRMR", "BH2RM", "BH2RM"}},
+		{4, Keys{"BHRM", "BH2R", "BH2R"}},
+		// BH2 is not cut into BH or B.
+		{2, Keys{"BH", "", ""}},
+		{1, Keys{"", "", ""}},
+	} {
+		phone := New(WithMaxKeyLen(c.n))
+		require.Equal(t, c.keys, phone.EncodeKeys("ଭ୍ରମରେ"), c.n)
+
+		var dst [3][]byte
+		phone.EncodeTo(&dst, "ଭ୍ରମରେ")
+		require.Equal(t, c.keys, Keys{string(dst[0]), string(dst[1]), string(dst[2])}, c.n)
+	}
+
+	// Truncated keys are prefixes of the full keys.
+	full := New().EncodeKeys("ବ୍ରାହ୍ମଣ")
+	k := New(WithMaxKeyLen(5)).EncodeKeys("ବ୍ରାହ୍ମଣ")
+	require.True(t, strings.HasPrefix(full.Key2, k.Key2))
+	require.True(t, strings.HasPrefix(full.Key1, k.Key1))
+	require.True(t, strings.HasPrefix(full.Key0, k.Key0))
+
+	// Exceptions and sentinels are truncated at rune boundaries.
+	phone := New(WithMaxKeyLen(2), WithSentinel("∅∅∅"))
+	phone.AddException("ଭ୍ରମର", Keys{"ṬṬṬ", "ṬṬṬ", "ṬṬṬ"})
+	require.Equal(t, Keys{"ṬṬ", "ṬṬ", "ṬṬ"}, phone.EncodeKeys("ଭ୍ରମର"))
+	require.Equal(t, Keys{"∅∅", "∅∅", "∅∅"}, phone.EncodeKeys("hello"))
+}
+
+func TestStackedConjuncts(t *testing.T) {
+	phone := New()
+	exp := phone.EncodeKeys("ଲକ୍ଷ")
+	for _, w := range []string{
+		"ଲକ୍\u200dଷ",       // ZWJ after the halant.
+		"ଲକ\u200d୍ଷ",       // ZWJ before the halant.
+		"ଲକ୍\u200cଷ",       // ZWNJ.
+		"ଲକ୍୍ଷ",            // Doubled halant.
+		"ଲକ୍\u200d୍ଷ",      // Doubled halant with a joiner.
+		"ଲକ୍\u0b55ଷ",       // Overline after the halant.
+		"ଲକ\u0b55୍ଷ",       // Overline before the halant.
+		"ଲକ\u0b55୍\u200dଷ", // Overline and a joiner.
+	} {
+		require.Equal(t, exp, phone.EncodeKeys(w), "%+q", w)
+	}
+	require.Equal(t, phone.EncodeKeys("ମନ୍ତ୍ର"), phone.EncodeKeys("ମନ୍୍ତ\u0b55୍ର"))
+}
+
+func TestRaPhala(t *testing.T) {
+	// ସ୍ର and ହ୍ର are already coherent consonant and R runs without a compound
+	// (S2R and HH2R, with the halant of the ra-phala in key1 and key2). Only ଶ୍ର
+	// is a compound, as it is pronounced "sr", like ସ୍ର.
+	phone := New()
+	require.Equal(t, Keys{"SR", "S2R5", "S:H2R5"}, phone.EncodeKeys("ଶ୍ରୀ"))
+	require.Equal(t, Keys{"SRM", "S2RM", "S:H2RM"}, phone.EncodeKeys("ଶ୍ରମ"))
+	require.Equal(t, Keys{"SRT", "S2R4T", "S2R4T"}, phone.EncodeKeys("ସ୍ରୋତ"))
+	require.Equal(t, Keys{"HHRD", "HH2RD", "HH2RD"}, phone.EncodeKeys("ହ୍ରଦ"))
+	require.Equal(t, Keys{"HHRS", "HH2R1S", "HH2R1S"}, phone.EncodeKeys("ହ୍ରାସ"))
+
+	// ଶ୍ରୀ is commonly misspelt with ସ, but the ra-phala is distinct from ର.
+	require.Equal(t, MatchKey1, phone.Compare("ଶ୍ରୀ", "ସ୍ରୀ"))
+	require.Equal(t, MatchNone, phone.Compare("ଶ୍ରୀ", "ଶରୀ"))
+
+	// The ra-phala keeps its halant in key1 and key2.
+	require.Equal(t, MatchKey0, phone.Compare("ହ୍ରଦ", "ହରଦ"))
+
+	// Other ra-phalas are unaffected.
+	require.Equal(t, Keys{"BHRM", "BH2RM", "BH2RM"}, New().EncodeKeys("ଭ୍ରମ"))
+}
+
+func TestHistoric(t *testing.T) {
+	// କୢପ୍ତ (kḷpta) has the archaic vocalic l sign ୢ, which is reported and
+	// not silently lost.
+	const word = "କୢପ୍ତ"
+	phone := New()
+	require.Equal(t, []rune{'ୢ'}, phone.UnmappedRunes(word))
+	require.Equal(t, []rune{'ଌ', 'ୡ'}, phone.UnmappedRunes("ଌକାରୡ"))
+	_, err := New(WithStrict()).TryEncode(word)
+	require.Error(t, err)
+	require.Equal(t, Keys{"KPT", "KP2T", "KP2T"}, phone.EncodeKeys(word))
+
+	// Mapped with the historic option.
+	phone = New(WithHistoric())
+	require.Empty(t, phone.UnmappedRunes(word))
+	require.Empty(t, phone.UnmappedRunes("ଌକାରୡ"))
+	require.Equal(t, Keys{"KPT", "K6P2T", "K6:LP2T"}, phone.EncodeKeys(word))
+	require.Equal(t, Keys{"LKRL", "LK1RL", "L:UK1RL:O"}, phone.EncodeKeys("ଌକାରୡ"))
+
+	// The signs are distinct from each other, and from ଲ + ୁ.
+	require.Equal(t, Keys{"K", "K", "K8:L"}, phone.EncodeKeys("କୣ"))
+	require.Equal(t, Keys{"KL", "KL6", "KL6"}, phone.EncodeKeys("କଲୁ"))
+	require.Equal(t, MatchNone, phone.Compare("କୢ", "କଲୁ"))
+
+	// A historic sign at the start of a word has no glyph to modify, and is
+	// removed like the other modifiers.
+	require.Equal(t, phone.EncodeKeys("କ"), phone.EncodeKeys("ୢକ"))
+	require.Equal(t, phone.EncodeKeys("କ"), phone.EncodeKeys("ୣକ"))
+
+	// Symbols are never mapped.
+	require.Equal(t, []rune{'୰'}, phone.UnmappedRunes("ଘର୰"))
+}
@@ -0,0 +1,199 @@
+package odiphone
+
+// Option configures an ODIphone tokenizer created with New.
+type Option func(*Options)
+
+// WithOptions replaces all the options with o, eg: to start from a saved
+// configuration that the following options then tweak. As the zero Options
+// is DefaultOptions(), the fields left unset in o are the defaults.
+func WithOptions(o Options) Option {
+	return func(opt *Options) {
+		*opt = o
+	}
+}
+
+// WithNFC clears Options.DisableNFC. NFC is on by default.
+func WithNFC() Option {
+	return func(opt *Options) {
+		opt.DisableNFC = false
+	}
+}
+
+// WithoutNFC enables Options.DisableNFC.
+func WithoutNFC() Option {
+	return func(opt *Options) {
+		opt.DisableNFC = true
+	}
+}
+
+// WithConfusableMap clears Options.DisableConfusables. The confusable map is
+// on by default.
+func WithConfusableMap() Option {
+	return func(opt *Options) {
+		opt.DisableConfusables = false
+	}
+}
+
+// WithoutConfusableMap enables Options.DisableConfusables.
+func WithoutConfusableMap() Option {
+	return func(opt *Options) {
+		opt.DisableConfusables = true
+	}
+}
+
+// WithDialect sets Options.Dialect.
+func WithDialect(d Dialect) Option {
+	return func(opt *Options) {
+		opt.Dialect = d
+	}
+}
+
+// WithStrict enables strict mode (Options.Strict).
+func WithStrict() Option {
+	return func(opt *Options) {
+		opt.Strict = true
+	}
+}
+
+// WithKeepUnmapped enables Options.KeepUnmapped.
+func WithKeepUnmapped() Option {
+	return func(opt *Options) {
+		opt.KeepUnmapped = true
+	}
+}
+
+// WithBatchCache enables Options.BatchCache.
+func WithBatchCache() Option {
+	return func(opt *Options) {
+		opt.BatchCache = true
+	}
+}
+
+// WithSplitAspiration enables Options.SplitAspiration.
+func WithSplitAspiration() Option {
+	return func(opt *Options) {
+		opt.SplitAspiration = true
+	}
+}
+
+// WithVelarNasal sets Options.VelarNasal.
+func WithVelarNasal(code string) Option {
+	return func(opt *Options) {
+		opt.VelarNasal = code
+	}
+}
+
+// WithInherentVowel enables Options.InherentVowel.
+func WithInherentVowel() Option {
+	return func(opt *Options) {
+		opt.InherentVowel = true
+	}
+}
+
+// WithSchwaDeletion enables Options.SchwaDeletion. It has no effect without
+// WithInherentVowel.
+func WithSchwaDeletion() Option {
+	return func(opt *Options) {
+		opt.SchwaDeletion = true
+	}
+}
+
+// WithLoanwordSchwaDeletion enables Options.LoanwordSchwaDeletion. It has
+// no effect without WithInherentVowel.
+func WithLoanwordSchwaDeletion() Option {
+	return func(opt *Options) {
+		opt.LoanwordSchwaDeletion = true
+	}
+}
+
+// WithSentinel sets Options.Sentinel.
+func WithSentinel(s string) Option {
+	return func(opt *Options) {
+		opt.Sentinel = s
+	}
+}
+
+// WithGeminates clears Options.DisableGeminates. Geminates are on by
+// default.
+func WithGeminates() Option {
+	return func(opt *Options) {
+		opt.DisableGeminates = false
+	}
+}
+
+// WithoutGeminates enables Options.DisableGeminates.
+func WithoutGeminates() Option {
+	return func(opt *Options) {
+		opt.DisableGeminates = true
+	}
+}
+
+// WithOCRMatras enables Options.OCRMatras.
+func WithOCRMatras() Option {
+	return func(opt *Options) {
+		opt.OCRMatras = true
+	}
+}
+
+// WithMaxKeyLen sets Options.MaxKeyLen.
+func WithMaxKeyLen(n int) Option {
+	return func(opt *Options) {
+		opt.MaxKeyLen = n
+	}
+}
+
+// WithHistoric enables Options.Historic.
+func WithHistoric() Option {
+	return func(opt *Options) {
+		opt.Historic = true
+	}
+}
+
+// WithHomorganicNasal enables Options.HomorganicNasal.
+func WithHomorganicNasal() Option {
+	return func(opt *Options) {
+		opt.HomorganicNasal = true
+	}
+}
+
+// WithChecksum enables Options.Checksum.
+func WithChecksum() Option {
+	return func(opt *Options) {
+		opt.Checksum = true
+	}
+}
+
+// WithNasalClusters enables Options.NasalClusters.
+func WithNasalClusters() Option {
+	return func(opt *Options) {
+		opt.NasalClusters = true
+	}
+}
+
+// WithVisualOrder enables Options.VisualOrder.
+func WithVisualOrder() Option {
+	return func(opt *Options) {
+		opt.VisualOrder = true
+	}
+}
+
+// WithGlideElision enables Options.GlideElision.
+func WithGlideElision() Option {
+	return func(opt *Options) {
+		opt.GlideElision = true
+	}
+}
+
+// WithHardSounds enables Options.HardSounds.
+func WithHardSounds() Option {
+	return func(opt *Options) {
+		opt.HardSounds = true
+	}
+}
+
+// WithInitialAspiration enables Options.InitialAspiration.
+func WithInitialAspiration() Option {
+	return func(opt *Options) {
+		opt.InitialAspiration = true
+	}
+}
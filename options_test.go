@@ -0,0 +1,320 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	require.Equal(t, DefaultOptions(), New().opt)
+	require.Equal(t, NewWithOptions(DefaultOptions()).EncodeKeys("ଭ୍ରମରେ"), New().EncodeKeys("ଭ୍ରମରେ"))
+
+	od := New(WithDialect(DialectWestern), WithInherentVowel(), WithStrict())
+	exp := DefaultOptions()
+	exp.Dialect = DialectWestern
+	exp.InherentVowel = true
+	exp.Strict = true
+	exp.SchwaDeletion = true // By the western dialect.
+	require.Equal(t, exp, od.opt)
+	require.Equal(t, Keys{"SL", "S1L", "S1L"}, od.EncodeKeys("ଶାଳ"))
+	require.Equal(t, "GH9R", od.EncodeKeys("ଘର").Key2)
+
+	// Options apply in order.
+	od = New(WithOptions(Options{Strict: true}), WithoutNFC(), WithDialect(DialectCoastal))
+	require.Equal(t, Options{Strict: true, DisableNFC: true, Dialect: DialectCoastal}, od.opt)
+	require.Equal(t, "SMR", od.EncodeKeys("ଶମର").Key0)
+	require.Equal(t, DialectStandard, New(WithDialect(DialectCoastal), WithOptions(DefaultOptions())).opt.Dialect)
+	require.Equal(t, DefaultOptions(), New(WithoutNFC(), WithoutConfusableMap(), WithoutGeminates(), WithNFC(), WithConfusableMap(), WithGeminates()).opt)
+
+	// The fields left unset in WithOptions are the defaults.
+	exp = DefaultOptions()
+	exp.Strict = true
+	require.Equal(t, exp, New(WithOptions(Options{Strict: true})).opt)
+
+	// Every field has an option.
+	od = New(WithKeepUnmapped(), WithBatchCache(), WithSplitAspiration(), WithVelarNasal("Ṅ"), WithInherentVowel(), WithSchwaDeletion())
+	require.Equal(t, Options{KeepUnmapped: true, BatchCache: true, SplitAspiration: true, VelarNasal: "Ṅ", InherentVowel: true, SchwaDeletion: true}, od.opt)
+}
+
+func TestNormalizerOptions(t *testing.T) {
+	// ୋ spelt as େ + ା, and with the Devanagari virama for the halant.
+	const word = "ଦ्ରୋ"
+	for _, c := range []struct {
+		opts []Option
+		keys Keys
+	}{
+		{nil, Keys{"DR", "D2R4", "D2R4"}},
+		{[]Option{WithoutNFC()}, Keys{"DR", "D2R31", "D2R31"}},
+		{[]Option{WithoutConfusableMap()}, Keys{"DR", "DR4", "DR4"}},
+		{[]Option{WithoutNFC(), WithoutConfusableMap()}, Keys{"DR", "DR31", "DR31"}},
+	} {
+		require.Equal(t, c.keys, New(c.opts...).EncodeKeys(word), c.opts)
+	}
+}
+
+func TestHomorganicNasal(t *testing.T) {
+	od := New(WithHomorganicNasal())
+	for _, c := range []struct {
+		word, spelt string
+	}{
+		// Velar.
+		{"ଶଂକର", "ଶଙ୍କର"},
+		{"ଗଂଗା", "ଗଙ୍ଗା"},
+		// Palatal.
+		{"ପଂଚ", "ପଞ୍ଚ"},
+		{"ଅଂଜଳି", "ଅଞ୍ଜଳି"},
+		// Labial.
+		{"କଂପନ", "କମ୍ପନ"},
+		{"ଅଂବା", "ଅମ୍ବା"},
+	} {
+		require.Equal(t, od.EncodeKeys(c.spelt), od.EncodeKeys(c.word), c.word)
+		require.NotEqual(t, New().EncodeKeys(c.spelt), New().EncodeKeys(c.word), c.word)
+	}
+
+	// An anusvara not before a stop is unaffected.
+	require.Equal(t, New().EncodeKeys("ଅଂଶ"), od.EncodeKeys("ଅଂଶ"))
+	require.Equal(t, New().EncodeKeys("ବଂଶ"), od.EncodeKeys("ବଂଶ"))
+	require.Equal(t, New().EncodeKeys("ସିଂ\u0b21\u0b3c"), od.EncodeKeys("ସିଂ\u0b21\u0b3c"))
+}
+
+func TestNasalClusters(t *testing.T) {
+	std, od := New(), New(WithNasalClusters())
+	for _, c := range []struct {
+		word, key0 string
+	}{
+		// Velar.
+		{"ଶଙ୍କର", "SHNKR"},
+		{"ଗଙ୍ଗା", "GNG"},
+		// Palatal.
+		{"ପଞ୍ଚ", "PNCH"},
+		{"ଅଞ୍ଜଳି", "ANJL"},
+		// Retroflex.
+		{"ଘଣ୍ଟା", "GHNT"},
+		{"ଦଣ୍ଡ", "DND"},
+		// Dental.
+		{"ଅନ୍ତ", "ANT"},
+		{"ବନ୍ଧ", "BNDH"},
+		// Labial.
+		{"କମ୍ପନ", "KNPN"},
+		{"ଅମ୍ବା", "ANB"},
+	} {
+		k := od.EncodeKeys(c.word)
+		require.Equal(t, c.key0, k.Key0, c.word)
+
+		// key1 and key2 are unaffected.
+		require.Equal(t, std.EncodeKeys(c.word).Key1, k.Key1, c.word)
+		require.Equal(t, std.EncodeKeys(c.word).Key2, k.Key2, c.word)
+
+		var dst [3][]byte
+		od.EncodeTo(&dst, c.word)
+		require.Equal(t, k, Keys{string(dst[0]), string(dst[1]), string(dst[2])}, c.word)
+	}
+
+	// The nasal of a cluster matches however it is written.
+	require.Equal(t, MatchKey0, od.Compare("କମ୍ପନ", "କନ୍ପନ"))
+	require.Equal(t, MatchNone, std.Compare("କମ୍ପନ", "କନ୍ପନ"))
+
+	// A nasal before a stop that is not homorganic is unaffected.
+	require.Equal(t, std.EncodeKeys("ଜନ୍ମ"), od.EncodeKeys("ଜନ୍ମ"))
+	require.Equal(t, "SNBHB", od.EncodeKeys("ସମ୍ଭବ").Key0)
+
+	// ଘଣ୍ଟା = GH N:R2 T:R1, and key0 = GH N T, truncated at their phonemes.
+	require.Equal(t, Keys{"GHNT", "GHN2", "GH"}, New(WithNasalClusters(), WithMaxKeyLen(4)).EncodeKeys("ଘଣ୍ଟା"))
+}
+
+func TestVisualOrder(t *testing.T) {
+	od := New(WithVisualOrder())
+	for _, c := range []struct {
+		visual, logical string
+	}{
+		{"େକ", "କେ"},
+		{"ଭ୍ରମେର", "ଭ୍ରମରେ"},
+		{"େମଘ", "ମେଘ"},
+		{"େଦବତା", "ଦେବତା"},
+		{"ୈତଳ", "ତୈଳ"},
+		// A conjunct takes the sign after its last consonant.
+		{"େକ୍ଷତ୍ର", "କ୍ଷେତ୍ର"},
+		// A split vowel sign composes once reordered.
+		{"େଘାଡ଼ା", "ଘୋଡ଼ା"},
+		{"େଲାକ", "ଲୋକ"},
+	} {
+		require.Equal(t, New().EncodeKeys(c.logical), od.EncodeKeys(c.visual), c.visual)
+	}
+
+	// A pre-base sign without a following consonant is left as is.
+	require.Equal(t, New().EncodeKeys("ଘରେ"), od.EncodeKeys("ଘରେ"))
+	require.NotEqual(t, New().EncodeKeys("କେ"), New().EncodeKeys("େକ"))
+}
+
+func TestGlideElision(t *testing.T) {
+	std, od := New(), New(WithGlideElision())
+	for _, c := range []struct {
+		word, key0, elided string
+	}{
+		{"ନୟନ", "NYN", "NN"},
+		{"ମାୟା", "MY", "M"},
+		{"ଉପାୟନ", "UPYN", "UPN"},
+		{"ଆୟୁ", "AAY", "AA"},
+		// The glide pronounced between vowels is elided however it is
+		// spelt.
+		{"ଦିଆ", "DY", "D"},
+		{"ଦିୟା", "DY", "D"},
+		// ୟ in a conjunct or at the end of a word is not a glide.
+		{"ସତ୍ୟ", "STY", "STY"},
+		{"ଭୟ", "BHY", "BHY"},
+		{"ଉପାୟ", "UPY", "UPY"},
+		// ଯ is not a glide.
+		{"ମାଯା", "MJ", "MJ"},
+	} {
+		require.Equal(t, c.key0, std.EncodeKeys(c.word).Key0, c.word)
+
+		k := od.EncodeKeys(c.word)
+		require.Equal(t, c.elided, k.Key0, c.word)
+
+		// key1 and key2 are unaffected.
+		require.Equal(t, std.EncodeKeys(c.word).Key1, k.Key1, c.word)
+		require.Equal(t, std.EncodeKeys(c.word).Key2, k.Key2, c.word)
+
+		var dst [3][]byte
+		od.EncodeTo(&dst, c.word)
+		require.Equal(t, k, Keys{string(dst[0]), string(dst[1]), string(dst[2])}, c.word)
+	}
+	require.Equal(t, MatchKey0, od.Compare("ନୟନ", "ନନ"))
+	require.Equal(t, MatchNone, std.Compare("ନୟନ", "ନନ"))
+}
+
+func TestHardSounds(t *testing.T) {
+	od := New(WithHardSounds())
+	for _, c := range []struct {
+		word string
+		keys Keys
+	}{
+		// key0 drops the phonetic modifier (ଃ) and the hard sound (the
+		// aspiration of ଖ), key1 only the phonetic modifier, and key2
+		// neither.
+		{"ଦୁଃଖ", Keys{"DK", "D6K0", "D67K0"}},
+		// Aspiration.
+		{"ଘର", Keys{"GR", "G0R", "G0R"}},
+		{"ଛାତ", Keys{"CHT", "CH01T", "CH01T"}},
+		{"ଦୁଗ୍ଧ", Keys{"DGD", "D6G2D0", "D6G2D0"}},
+		// Gemination.
+		{"ଅନ୍ନ", Keys{"AN", "AN0", "AN0:G"}},
+		{"ଅନ୍ନେ", Keys{"AN", "AN03", "AN0:G3"}},
+		{"ସତ୍ତ୍ୱ", Keys{"STB", "ST02B", "ST0:G2B:W"}},
+		// The nukta of a flap, after the aspiration of ଢ.
+		{"ଖଡ଼ି", Keys{"KD", "K0D05", "K0D0:F5"}},
+		{"ପଢ଼ା", Keys{"PD", "PD001", "PD00:F1"}},
+		// Words without hard sounds are unaffected.
+		{"ଗର", Keys{"GR", "GR", "GR"}},
+		{"ଅଂଶ", Keys{"ASH", "ASH", "A7SH"}},
+	} {
+		k := od.EncodeKeys(c.word)
+		require.Equal(t, c.keys, k, c.word)
+
+		var dst [3][]byte
+		od.EncodeTo(&dst, c.word)
+		require.Equal(t, k, Keys{string(dst[0]), string(dst[1]), string(dst[2])}, c.word)
+	}
+
+	// Geminates and aspirates are both hard sounds, but are distinct.
+	require.Equal(t, MatchKey1, od.Compare("ଅକ୍କ", "ଅଖ"))
+
+	// Words that differ only by hard sounds match at key0 but not at key1.
+	for _, p := range [][2]string{{"ଘର", "ଗର"}, {"ଅନ୍ନ", "ଅନ"}, {"ଖଡ଼ି", "କଡି"}, {"ଛାତ", "ଚାତ"}, {"ଫଳ", "ପଳ"}} {
+		require.Equal(t, MatchKey0, od.Compare(p[0], p[1]), p)
+		require.Equal(t, MatchNone, New().Compare(p[0], p[1]), p)
+	}
+
+	// With InitialAspiration, the word-initial aspirates are kept apart in
+	// key0, and the other hard sounds are still merged.
+	initial := New(WithHardSounds(), WithInitialAspiration())
+	for _, p := range [][2]string{{"ଘର", "ଗର"}, {"ଖଡ଼ି", "କଡି"}, {"ଛାତ", "ଚାତ"}, {"ଫଳ", "ପଳ"}} {
+		require.Equal(t, MatchNone, initial.Compare(p[0], p[1]), p)
+	}
+	for _, p := range [][2]string{{"ଅନ୍ନ", "ଅନ"}, {"ଦୁଃଖ", "ଦୁକ"}, {"ପଢ଼ା", "ପଡା"}} {
+		require.Equal(t, MatchKey0, initial.Compare(p[0], p[1]), p)
+	}
+
+	// It applies to geminates even with DisableGeminates, and
+	// SplitAspiration takes precedence over it.
+	require.Equal(t, Keys{"AN", "AN0", "AN0:G"}, New(WithHardSounds(), WithoutGeminates()).EncodeKeys("ଅନ୍ନ"))
+	split := New(WithHardSounds(), WithSplitAspiration())
+	require.Equal(t, Keys{"GʰR", "GʰR", "GʰR"}, split.EncodeKeys("ଘର"))
+	require.Equal(t, Keys{"AN", "AN0", "AN0:G"}, split.EncodeKeys("ଅନ୍ନ"))
+}
+
+func TestInitialAspiration(t *testing.T) {
+	// Without HardSounds, aspiration is kept in every key, with or without
+	// the option.
+	pairs := [][2]string{{"ଫଳ", "ପଳ"}, {"ଘର", "ଗର"}, {"ଖାଲି", "କାଲି"}, {"କ୍ଷମା", "କମା"}}
+	for _, phone := range []*ODIphone{New(), New(WithInitialAspiration()), New(WithSplitAspiration())} {
+		for _, p := range pairs {
+			require.Equal(t, MatchNone, phone.Compare(p[0], p[1]), p)
+		}
+	}
+	require.Equal(t, Keys{"PHL", "PHL", "PHL:R"}, New().EncodeKeys("ଫଳ"))
+
+	// With HardSounds, word-initial minimal pairs only match at key0 without
+	// the option.
+	hard, initial := New(WithHardSounds()), New(WithHardSounds(), WithInitialAspiration())
+	for _, p := range pairs {
+		require.Equal(t, MatchKey0, hard.Compare(p[0], p[1]), p)
+		require.Equal(t, MatchNone, initial.Compare(p[0], p[1]), p)
+	}
+	for _, c := range []struct {
+		word string
+		keys Keys
+	}{
+		{"ଫଳ", Keys{"P0L", "P0L", "P0L:R"}},
+		{"ପଳ", Keys{"PL", "PL", "PL:R"}},
+		{"ଖାଲି", Keys{"K0L", "K01L5", "K01L5"}},
+		{"କ୍ଷମା", Keys{"K0M", "K0M1", "K0:SM1"}},
+		// Only the aspiration of the initial consonant is kept.
+		{"ଛାତ", Keys{"CH0T", "CH01T", "CH01T"}},
+		{"ଦୁଃଖ", Keys{"DK", "D6K0", "D67K0"}},
+		{"ଘୋଡ଼ା", Keys{"G0D", "G04D01", "G04D0:F1"}},
+	} {
+		k := initial.EncodeKeys(c.word)
+		require.Equal(t, c.keys, k, c.word)
+
+		var dst [3][]byte
+		initial.EncodeTo(&dst, c.word)
+		require.Equal(t, k, Keys{string(dst[0]), string(dst[1]), string(dst[2])}, c.word)
+	}
+}
+
+func TestLoanwordSchwaDeletion(t *testing.T) {
+	std, od := New(WithInherentVowel()), New(WithInherentVowel(), WithLoanwordSchwaDeletion())
+	for _, c := range []struct {
+		word, key2, deleted string
+	}{
+		// Loanwords end in a retroflex stop after a consonant that is not
+		// retroflex.
+		{"ପୋସ୍ଟ", "P4S2T:R9", "P4S2T:R"},
+		{"କାର୍ଡ", "K1R2D:R9", "K1R2D:R"},
+		{"ବେଲ୍ଟ", "B3L2T:R9", "B3L2T:R"},
+		{"ଏକ୍ଟ", "EK2T:R9", "EK2T:R"},
+		// Native words keep the final schwa.
+		{"ଦଣ୍ଡ", "D9N:R2D:R9", "D9N:R2D:R9"},
+		{"କଷ୍ଟ", "K9SH2T:R9", "K9SH2T:R9"},
+		{"ମନ୍ତ୍ର", "M9N2TR9", "M9N2TR9"},
+		{"ଘର", "GH9R9", "GH9R9"},
+		// Loanwords spelt with a native cluster are not recognized.
+		{"ଟେଷ୍ଟ", "T:R3SH2T:R9", "T:R3SH2T:R9"},
+		// A final vowel is unaffected.
+		{"ପୋସ୍ଟେ", "P4S2T:R3", "P4S2T:R3"},
+	} {
+		require.Equal(t, c.key2, std.EncodeKeys(c.word).Key2, c.word)
+
+		k := od.EncodeKeys(c.word)
+		require.Equal(t, c.deleted, k.Key2, c.word)
+
+		// key0 and key1 are unaffected.
+		require.Equal(t, std.EncodeKeys(c.word).Key0, k.Key0, c.word)
+		require.Equal(t, std.EncodeKeys(c.word).Key1, k.Key1, c.word)
+	}
+
+	// It has no effect without InherentVowel.
+	require.Equal(t, New().EncodeKeys("ପୋସ୍ଟ"), New(WithLoanwordSchwaDeletion()).EncodeKeys("ପୋସ୍ଟ"))
+}
@@ -0,0 +1,98 @@
+package odiphone
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// glyphClass is the orthographic class of a rune for IsPlausibleOdia.
+type glyphClass int
+
+const (
+	classNone glyphClass = iota
+	classConsonant
+	classVowel
+	classVowelSign
+	classHalant
+	classNasal
+)
+
+// IsPlausibleOdia reports if a word is orthographically plausible Odia by
+// simple syllable-structure rules: it only has Odia letters and signs, vowel
+// signs, halants, and nuktas follow a consonant, nasal signs follow a
+// consonant or a vowel, and an independent vowel does not follow another
+// unless the two are pronounced together (eg: ଉଆସ and ଆଇନ, but not ଏଏ). It
+// filters implausible suggestions and is not a spell checker.
+func IsPlausibleOdia(word string) bool {
+	word = norm.NFC.String(strings.TrimSpace(word))
+	if word == "" {
+		return false
+	}
+
+	var (
+		prev     = classNone
+		prevRune rune
+		nuktaOK  bool
+	)
+	for _, r := range word {
+		var c glyphClass
+		switch {
+		case isConsonant(r):
+			c = classConsonant
+			nuktaOK = true
+
+		case r == nukta:
+			// A nukta is part of the consonant it follows.
+			if prev != classConsonant || !nuktaOK {
+				return false
+			}
+			nuktaOK = false
+			prevRune = r
+			continue
+
+		case isVowel(r):
+			if prev == classHalant || (prev == classVowel && !takesGlide(prevRune, r)) {
+				return false
+			}
+			c = classVowel
+
+		case strings.ContainsRune(vowelSigns, r), r == halant:
+			if prev != classConsonant {
+				return false
+			}
+			c = classVowelSign
+			if r == halant {
+				c = classHalant
+			}
+
+		case isNasal(r):
+			if prev == classNone || prev == classHalant || prev == classNasal {
+				return false
+			}
+			c = classNasal
+
+		default:
+			return false
+		}
+		prev, prevRune = c, r
+	}
+	return true
+}
+
+// takesGlide reports if two consecutive independent vowels are pronounced
+// together: with a glide between them, as with the vowel signs in
+// vowelGlides, or as a diphthong ending in i or u, eg: ଆଇନ, ଆଈ, ଆଉ and ଉଇ.
+func takesGlide(a, b rune) bool {
+	switch {
+	case b == 'ଇ' || b == 'ଈ':
+		return strings.ContainsRune("ଅଆଏଓଉ", a)
+	case b == 'ଉ':
+		return strings.ContainsRune("ଅଆଏଓ", a)
+	case a == 'ଇ' || a == 'ଈ':
+		return strings.ContainsRune("ଅଆଏଓ", b)
+	case a == 'ଉ' || a == 'ଊ':
+		return strings.ContainsRune("ଅଆଏ", b)
+	}
+	return false
+}
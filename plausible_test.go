@@ -0,0 +1,42 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPlausibleOdia(t *testing.T) {
+	for _, w := range []string{
+		"ଘର", "ଭ୍ରମରେ", "ଲକ୍ଷ୍ୟ", "ଅଂଶ", "ଆ", "ଉଆସ", "ଦିଅ", "ନଈ", "ଓଡ଼ିଆ",
+		"ଆଇନ", "ଆଈ", "ଆଉ", "ଉଇ", "ଏଇ",
+		"ବଡ଼", "ସମ୍ରାଟ୍", "ଚାଁଦ", "ଦୁଃଖ", " ଘର ",
+		// NFC composes େ + ା into ୋ.
+		"ଘୋଡ଼ା",
+	} {
+		require.True(t, IsPlausibleOdia(w), w)
+	}
+
+	for _, w := range []string{
+		"", "hello", "ଘର1",
+		// Signs without a consonant.
+		"ାଘର", "ଆି", "୍କ", "ଂଘ", "଼କ",
+		// Doubled signs.
+		"କାି", "କ୍୍ଷ", "କ଼଼", "ଘରଂଂ",
+		// A vowel after a halant.
+		"କ୍ଅ",
+		// Independent vowels in a row that are not pronounced together.
+		"ଏଏ", "ଆଏ", "ଓଆ", "ଉଓ", "ଇଇ", "ଉଉ", "ଈଇ", "ଇଈ", "ଇଉ",
+	} {
+		require.False(t, IsPlausibleOdia(w), w)
+	}
+}
+
+func TestSuggestPlausibleOnly(t *testing.T) {
+	s := NewSuggester(New(), []string{"ଭ୍ରମର", "ଭ୍ରମରା୍", "ଭ୍ରମରାି"})
+	require.Equal(t, []string{"ଭ୍ରମର", "ଭ୍ରମରା୍", "ଭ୍ରମରାି"}, s.Suggest("ଭ୍ରମର", 0))
+
+	s.PlausibleOnly = true
+	require.Equal(t, []string{"ଭ୍ରମର"}, s.Suggest("ଭ୍ରମର", 0))
+	require.Equal(t, []string{"ଭ୍ରମର"}, s.Suggest("ଭ୍ରମରା", 0))
+}
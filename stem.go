@@ -0,0 +1,81 @@
+package odiphone
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// suffixes are common Odia inflectional endings (plural, case markers and
+// classifiers) stripped by Stem. They are ordered longest first so that the
+// longest matching ending wins.
+var suffixes = []string{
+	"ମାନଙ୍କରେ",
+	"ମାନଙ୍କୁ",
+	"ମାନଙ୍କର",
+	"ମାନଙ୍କ",
+	"ଦ୍ୱାରା",
+	"ଗୁଡ଼ିକ",
+	"ଗୁଡିକ",
+	"ମାନେ",
+	"ଙ୍କୁ",
+	"ଙ୍କର",
+	"ପାଇଁ",
+	"ଠାରୁ",
+	"ଠାରେ",
+	"ଙ୍କ",
+	"କୁ",
+	"ରେ",
+	"ରୁ",
+	"ଟି",
+	"ଟା",
+	"ର",
+}
+
+// minStemLen is the minimum number of runes a stem must retain for a
+// suffix to be stripped.
+const minStemLen = 2
+
+// Stem strips a single common inflectional ending (ମାନେ plural, ର genitive,
+// କୁ dative etc.) from an Odia word so that inflected forms of a word share
+// the phonetic keys of the root, eg: od.Encode(Stem(word)). It is a simple
+// suffix stripper and not a morphological analyser. The word is normalized
+// as New() normalizes it before the endings are matched, so that an ending
+// spelt with a precomposed or confusable glyph (eg: ଗୁଡ଼ିକ with the
+// precomposed ଡ଼) is stripped, and the stem is returned normalized.
+func Stem(word string) string {
+	return stem(StripNonOdia(FixConfusables(NFCNormalize(strings.TrimSpace(word)))))
+}
+
+// Stem is the same as the package Stem, but normalizes the word as the
+// tokenizer does, eg: without NFC with Options.DisableNFC.
+func (od *ODIphone) Stem(word string) string {
+	return stem(od.normalize(word))
+}
+
+// stem strips an ending from a normalized word.
+func stem(word string) string {
+	for _, s := range suffixes {
+		if !strings.HasSuffix(word, s) {
+			continue
+		}
+
+		stem := word[:len(word)-len(s)]
+		if utf8.RuneCountInString(stem) < minStemLen || strings.HasSuffix(stem, "୍") {
+			continue
+		}
+		return stem
+	}
+	return word
+}
+
+// EncodeForms returns the keys of the root and of each of its inflected
+// forms with the given suffixes (eg: ମାନେ, କୁ), keyed by the form, for
+// building an inflection expanded index.
+func (od *ODIphone) EncodeForms(root string, suffixes []string) map[string]Keys {
+	out := make(map[string]Keys, len(suffixes)+1)
+	out[root] = od.EncodeKeys(root)
+	for _, s := range suffixes {
+		out[root+s] = od.EncodeKeys(root + s)
+	}
+	return out
+}
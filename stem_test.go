@@ -0,0 +1,56 @@
+package odiphone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, stem string
+	}{
+		{"ବହି", "ବହି"},
+		{"ବହିମାନେ", "ବହି"},
+		{"ବହିକୁ", "ବହି"},
+		{"ବହିର", "ବହି"},
+		{"ପିଲାମାନଙ୍କୁ", "ପିଲା"},
+		// Too short to strip.
+		{"ଘର", "ଘର"},
+		{"", ""},
+		// Endings are matched once normalized.
+		{"ବହିଗୁ\u0b5cିକ", "ବହି"},
+		{"ପିଲାମାନଙ\u094dକୁ", "ପିଲା"},
+		{" ବହିକୁ।", "ବହି"},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.stem, Stem(v.word), v.word)
+		require.Equal(t, v.stem, New().Stem(v.word), v.word)
+	}
+
+	// The stem is normalized, and the tokenizer normalizes as configured.
+	require.Equal(t, "ବଡ଼", Stem("ବ\u0b5cକୁ"))
+	// Without the confusable map, the Devanagari virama is stripped as a
+	// non-Odia character.
+	require.Equal(t, "ପିଲାମାନଙ", New(WithoutConfusableMap()).Stem("ପିଲାମାନଙ\u094dକୁ"))
+}
+
+func TestStemEncode(t *testing.T) {
+	phone := New()
+	root := phone.EncodeKeys(Stem("ବହି"))
+	require.Equal(t, root, phone.EncodeKeys(Stem("ବହିମାନେ")))
+	require.Equal(t, root, phone.EncodeKeys(Stem("ବହିକୁ")))
+	require.NotEqual(t, root, phone.EncodeKeys("ବହିମାନେ"))
+}
+
+func TestEncodeForms(t *testing.T) {
+	phone := New()
+	forms := phone.EncodeForms("ବହି", []string{"ମାନେ", "କୁ"})
+	require.Equal(t, map[string]Keys{
+		"ବହି":     {"BHH", "BHH5", "BHH5"},
+		"ବହିମାନେ": {"BHHMN", "BHH5M1N3", "BHH5M1N3"},
+		"ବହିକୁ":   {"BHHK", "BHH5K6", "BHH5K6"},
+	}, forms)
+
+	require.Equal(t, map[string]Keys{"ବହି": phone.EncodeKeys("ବହି")}, phone.EncodeForms("ବହି", nil))
+}
@@ -0,0 +1,194 @@
+package odiphone
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrStaleIndex is returned by LoadSuggester when the saved index was built
+// with a different AlgorithmVersion and has to be rebuilt.
+var ErrStaleIndex = errors.New("odiphone: index was built with a different algorithm version")
+
+// Suggester suggests words from a dictionary that sound like a query word.
+// The dictionary words are bucketed by their keys at every level.
+type Suggester struct {
+	// PlausibleOnly drops the suggestions that are not IsPlausibleOdia, eg:
+	// misspellings in a dictionary built from user input.
+	PlausibleOnly bool
+
+	od *ODIphone
+
+	// buckets are the words by their key0, key1, and key2.
+	buckets [3]map[string][]string
+	seen    map[string]bool
+}
+
+// suggesterIndex is the serialized form of a Suggester. The words, buckets,
+// tables, and exceptions are sorted so that the serialization is
+// deterministic.
+type suggesterIndex struct {
+	AlgorithmVersion int
+	Options          Options
+	PlausibleOnly    bool
+	Words            []string
+	Buckets          [3][]suggesterBucket
+
+	// Tables are the vowels, consonants, compounds, and modifiers of the
+	// encoder, which may have been customized with SetCode and AddCompound.
+	Tables     [4][]suggesterCode
+	Exceptions []suggesterException
+}
+
+type suggesterBucket struct {
+	Key   string
+	Words []string
+}
+
+type suggesterCode struct {
+	Glyph, Code string
+}
+
+type suggesterException struct {
+	Word string
+	Keys Keys
+}
+
+// NewSuggester returns a new Suggester for the dictionary of words that
+// uses the given encoder.
+func NewSuggester(od *ODIphone, dict []string) *Suggester {
+	s := &Suggester{od: od, seen: make(map[string]bool)}
+	for i := range s.buckets {
+		s.buckets[i] = make(map[string][]string)
+	}
+	for _, w := range dict {
+		s.Add(w)
+	}
+	return s
+}
+
+// Add adds a word to the dictionary.
+func (s *Suggester) Add(word string) {
+	if s.seen[word] {
+		return
+	}
+
+	k := s.od.EncodeKeys(word)
+	if k.Key0 == "" {
+		return
+	}
+	s.seen[word] = true
+	for i, key := range []string{k.Key0, k.Key1, k.Key2} {
+		s.buckets[i][key] = append(s.buckets[i][key], word)
+	}
+}
+
+// Suggest returns up to max dictionary words that share a key with the
+// query, the ones sharing the narrowest key first. If max is less than 1,
+// all matching words are returned.
+func (s *Suggester) Suggest(query string, max int) []string {
+	var (
+		k    = s.od.EncodeKeys(query)
+		keys = []string{k.Key0, k.Key1, k.Key2}
+		seen = make(map[string]bool)
+		out  []string
+	)
+	for i := len(keys) - 1; i >= 0; i-- {
+		for _, w := range s.buckets[i][keys[i]] {
+			if seen[w] || (s.PlausibleOnly && !IsPlausibleOdia(w)) {
+				continue
+			}
+			if max > 0 && len(out) == max {
+				return out
+			}
+			seen[w] = true
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// Save writes the suggester's index to w so that it can be loaded with
+// LoadSuggester without re-encoding the dictionary.
+func (s *Suggester) Save(w io.Writer) error {
+	idx := suggesterIndex{
+		AlgorithmVersion: AlgorithmVersion,
+		Options:          s.od.opt,
+		PlausibleOnly:    s.PlausibleOnly,
+		Words:            make([]string, 0, len(s.seen)),
+	}
+	for w := range s.seen {
+		idx.Words = append(idx.Words, w)
+	}
+	sort.Strings(idx.Words)
+
+	for i, b := range s.buckets {
+		for k, words := range b {
+			idx.Buckets[i] = append(idx.Buckets[i], suggesterBucket{Key: k, Words: words})
+		}
+		sort.Slice(idx.Buckets[i], func(a, b int) bool {
+			return idx.Buckets[i][a].Key < idx.Buckets[i][b].Key
+		})
+	}
+
+	for i, m := range []map[string]string{s.od.vowels, s.od.consonants, s.od.compounds, s.od.modifiers} {
+		for g, c := range m {
+			idx.Tables[i] = append(idx.Tables[i], suggesterCode{Glyph: g, Code: c})
+		}
+		sort.Slice(idx.Tables[i], func(a, b int) bool {
+			return idx.Tables[i][a].Glyph < idx.Tables[i][b].Glyph
+		})
+	}
+	for w, k := range s.od.exceptions {
+		idx.Exceptions = append(idx.Exceptions, suggesterException{Word: w, Keys: k})
+	}
+	sort.Slice(idx.Exceptions, func(a, b int) bool {
+		return idx.Exceptions[a].Word < idx.Exceptions[b].Word
+	})
+
+	return gob.NewEncoder(w).Encode(idx)
+}
+
+// LoadSuggester loads a suggester's index saved with Save. The loaded
+// suggester encodes queries with a new encoder that has the options, tables,
+// and exceptions of the saved one, and has its PlausibleOnly. If the index
+// was saved with a different AlgorithmVersion, ErrStaleIndex is returned.
+func LoadSuggester(r io.Reader) (*Suggester, error) {
+	var idx suggesterIndex
+	if err := gob.NewDecoder(r).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("odiphone: error loading index: %v", err)
+	}
+	if idx.AlgorithmVersion != AlgorithmVersion {
+		return nil, ErrStaleIndex
+	}
+
+	s := &Suggester{
+		PlausibleOnly: idx.PlausibleOnly,
+		od:            NewWithOptions(idx.Options),
+		seen:          make(map[string]bool, len(idx.Words)),
+	}
+	if idx.Tables[0] != nil {
+		for i, m := range []*map[string]string{&s.od.vowels, &s.od.consonants, &s.od.compounds, &s.od.modifiers} {
+			*m = make(map[string]string, len(idx.Tables[i]))
+			for _, c := range idx.Tables[i] {
+				(*m)[c.Glyph] = c.Code
+			}
+		}
+		s.od.compile()
+	}
+	for _, e := range idx.Exceptions {
+		s.od.exceptions[e.Word] = e.Keys
+	}
+	for i, b := range idx.Buckets {
+		s.buckets[i] = make(map[string][]string, len(b))
+		for _, bk := range b {
+			s.buckets[i][bk.Key] = bk.Words
+		}
+	}
+	for _, w := range idx.Words {
+		s.seen[w] = true
+	}
+	return s, nil
+}
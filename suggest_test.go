@@ -0,0 +1,65 @@
+package odiphone
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var dict = []string{"ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ", "ଲକ୍ଷ", "ଲଖ", "ଅଂଶ", "ଭ୍ରମର", "hello"}
+
+func TestSuggest(t *testing.T) {
+	s := NewSuggester(New(), dict)
+	require.Equal(t, []string{"ଭ୍ରମର", "ଭ୍ରମରେ"}, s.Suggest("ଭ୍ରମର", 0))
+	require.Equal(t, []string{"ଭ୍ରମରେ", "ଭ୍ରମର"}, s.Suggest("ଭ୍ରମରେ", 0))
+	require.Equal(t, []string{"ଲଖ", "ଲକ୍ଷ"}, s.Suggest("ଲଖ", 0))
+	require.Equal(t, []string{"ଭ୍ରମର"}, s.Suggest("ଭ୍ରମର", 1))
+	require.Empty(t, s.Suggest("ଘର", 0))
+	require.Empty(t, s.Suggest("hello", 0))
+}
+
+func TestSuggesterSaveLoad(t *testing.T) {
+	// The custom tables, exceptions, and PlausibleOnly are saved along with
+	// the options.
+	od := New(WithVelarNasal("Ṅ"))
+	require.NoError(t, od.SetCode("ଣ", "N"))
+	require.NoError(t, od.AddCompound("ମ୍ର", "MR"))
+	od.AddException("ଘର", Keys{"GHR", "GHR", "GHAR"})
+	s := NewSuggester(od, dict)
+	s.PlausibleOnly = true
+
+	var b, b2 bytes.Buffer
+	require.NoError(t, s.Save(&b))
+
+	// The serialization is deterministic.
+	require.NoError(t, s.Save(&b2))
+	require.Equal(t, b.Bytes(), b2.Bytes())
+
+	l, err := LoadSuggester(&b)
+	require.NoError(t, err)
+	require.Equal(t, s.buckets, l.buckets)
+	require.Equal(t, s.seen, l.seen)
+	require.Equal(t, "Ṅ", l.od.opt.VelarNasal)
+	require.True(t, l.PlausibleOnly)
+	for _, w := range []string{"ଭ୍ରମର", "ଭ୍ରମରେ", "ଲଖ", "ଘର", "ଭ୍ରମନ", "ଅଙ୍କ", "ଙ", "ମ୍ରୁଗ"} {
+		require.Equal(t, s.Suggest(w, 0), l.Suggest(w, 0), w)
+		require.Equal(t, od.EncodeKeys(w), l.od.EncodeKeys(w), w)
+	}
+	require.Equal(t, []string{"ଭ୍ରମଣ"}, l.Suggest("ଭ୍ରମନ", 0))
+
+	// Words added after loading are suggested.
+	l.Add("ଘର")
+	require.Equal(t, []string{"ଘର"}, l.Suggest("ଘର", 0))
+}
+
+func TestLoadSuggesterStale(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&b).Encode(suggesterIndex{AlgorithmVersion: AlgorithmVersion - 1}))
+	_, err := LoadSuggester(&b)
+	require.ErrorIs(t, err, ErrStaleIndex)
+
+	_, err = LoadSuggester(bytes.NewBufferString("junk"))
+	require.Error(t, err)
+}
@@ -0,0 +1,125 @@
+package odiphone
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// syllables splits a normalized input into its orthographic syllables
+// (aksharas): an independent vowel, or a consonant with the consonants
+// joined to it by halants and its vowel sign, each followed by any nasal
+// or visarga signs, eg: ଭ୍ରମର = [ଭ୍ର ମ ର].
+func syllables(input string) []string {
+	var (
+		out []string
+		rs  = []rune(input)
+	)
+	for i := 0; i < len(rs); {
+		start := i
+
+		switch {
+		case isConsonant(rs[i]):
+			i = consonantEnd(rs, i)
+
+			// Consonants joined by halants.
+			for i+1 < len(rs) && rs[i] == halant && isConsonant(rs[i+1]) {
+				i = consonantEnd(rs, i+1)
+			}
+
+			// A vowel sign or a trailing halant.
+			if i < len(rs) && (rs[i] == halant || strings.ContainsRune(vowelSigns, rs[i])) {
+				i++
+			}
+
+		case isVowel(rs[i]):
+			i++
+
+		default:
+			// A stray sign belongs to the preceding syllable.
+			i++
+			if len(out) > 0 {
+				out[len(out)-1] += string(rs[start:i])
+				continue
+			}
+		}
+
+		for i < len(rs) && isNasal(rs[i]) {
+			i++
+		}
+		out = append(out, string(rs[start:i]))
+	}
+	return out
+}
+
+// consonantEnd returns the index after the consonant at i and its nukta.
+func consonantEnd(rs []rune, i int) int {
+	i++
+	if i < len(rs) && rs[i] == nukta {
+		i++
+	}
+	return i
+}
+
+func isVowel(r rune) bool {
+	_, ok := vowels[string(r)]
+	return ok
+}
+
+func isNasal(r rune) bool {
+	return r == 'ଂ' || r == 'ଁ' || r == 'ଃ'
+}
+
+// Spell returns a readable, hyphen separated syllable spelling of a word,
+// eg: ଭ୍ରମର = bhra-ma-ra, for assistive reading.
+func (od *ODIphone) Spell(word string) string {
+	return strings.Join(od.spellSyllables(word), "-")
+}
+
+// stressMark is the IPA primary stress mark, which precedes the stressed
+// syllable.
+const stressMark = "ˈ"
+
+// StressedForm returns the syllable spelling of a word (as Spell) with its
+// primary stress marked, eg: ଭ୍ରମର = ˈbhra-ma-ra, for prosody-aware tools.
+// Stress in Odia is predictable and falls on the first syllable, whatever
+// the length of its vowel.
+func (od *ODIphone) StressedForm(word string) string {
+	syls := od.spellSyllables(word)
+	if len(syls) == 0 {
+		return ""
+	}
+	syls[0] = stressMark + syls[0]
+	return strings.Join(syls, "-")
+}
+
+// spellSyllables returns the Roman transliterations of the syllables of a
+// word.
+func (od *ODIphone) spellSyllables(word string) []string {
+	syls := syllables(od.normalize(word))
+	for i, s := range syls {
+		syls[i] = strings.Join(roman.transliterate(s), "")
+	}
+	return syls
+}
+
+// Decompose returns the glyphs of a word in reading order, ie: its vowels,
+// consonants (with their nuktas), vowel signs, halants, and other signs,
+// eg: ଭ୍ରମର = [ଭ ୍ ର ମ ର], to show how its conjuncts are put together. It
+// is an orthographic decomposition of the NFC form, and not a phonetic one.
+// Non-Odia characters are dropped.
+func Decompose(word string) []string {
+	var out []string
+	for _, r := range norm.NFC.String(word) {
+		switch {
+		case !unicode.Is(unicode.Oriya, r):
+			continue
+		case r == nukta && len(out) > 0:
+			out[len(out)-1] += string(r)
+		default:
+			out = append(out, string(r))
+		}
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package odiphone
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		syls []string
+	}{
+		{"ଭ୍ରମର", []string{"ଭ୍ର", "ମ", "ର"}},
+		{"ଅଂଶ", []string{"ଅଂ", "ଶ"}},
+		{"ସତ୍ୟ", []string{"ସ", "ତ୍ୟ"}},
+		{"ମନ୍ତ୍ର", []string{"ମ", "ନ୍ତ୍ର"}},
+		{"ରକ୍ଷା", []string{"ର", "କ୍ଷା"}},
+		{"ଚାଁଦ", []string{"ଚାଁ", "ଦ"}},
+		{"ବଡ଼", []string{"ବ", "ଡ଼"}},
+		{"ଘର୍", []string{"ଘ", "ର୍"}},
+		{"ଆଇନ", []string{"ଆ", "ଇ", "ନ"}},
+		{"", nil},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.syls, syllables(New().normalize(v.word)), v.word)
+	}
+}
+
+func TestSpell(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		word, spell string
+	}{
+		{"ଭ୍ରମର", "bhra-ma-ra"},
+		{"ଭ୍ରମରେ", "bhra-ma-re"},
+		{"ଅଂଶ", "an-sha"},
+		{"ସତ୍ୟ", "sa-tya"},
+		{"ବିଦ୍ୟା", "bi-dyaa"},
+		{"", ""},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.spell, phone.Spell(v.word), v.word)
+	}
+}
+
+func TestStressedForm(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		word, stressed string
+	}{
+		{"ଭ୍ରମର", "ˈbhra-ma-ra"},
+		{"ଭ୍ରମରେ", "ˈbhra-ma-re"},
+		// The first syllable is stressed whether it is light or heavy.
+		{"ବିଦ୍ୟା", "ˈbi-dyaa"},
+		{"ଆକାଶ", "ˈaa-kaa-sha"},
+		{"ଅଂଶ", "ˈan-sha"},
+		{"ଘର", "ˈgha-ra"},
+		{"ଆ", "ˈaa"},
+		{"", ""},
+		{"hello", ""},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.stressed, phone.StressedForm(v.word), v.word)
+
+		// The stress mark is the only difference from the spelling.
+		require.Equal(t, phone.Spell(v.word), strings.TrimPrefix(phone.StressedForm(v.word), "ˈ"), v.word)
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	tests := []struct {
+		word   string
+		glyphs []string
+	}{
+		{"ଭ୍ରମର", []string{"ଭ", "୍", "ର", "ମ", "ର"}},
+		{"ମନ୍ତ୍ର", []string{"ମ", "ନ", "୍", "ତ", "୍", "ର"}},
+		{"ଲକ୍ଷ୍ମୀ", []string{"ଲ", "କ", "୍", "ଷ", "୍", "ମ", "ୀ"}},
+		{"ଆକାଙ୍କ୍ଷା", []string{"ଆ", "କ", "ା", "ଙ", "୍", "କ", "୍", "ଷ", "ା"}},
+		{"ସ୍ୱାସ୍ଥ୍ୟ", []string{"ସ", "୍", "ୱ", "ା", "ସ", "୍", "ଥ", "୍", "ୟ"}},
+		{"ଚାଁଦ", []string{"ଚ", "ା", "ଁ", "ଦ"}},
+		// The nukta is part of its consonant in either form.
+		{"ବଡ଼", []string{"ବ", "ଡ଼"}},
+		{"ବ\u0b5c", []string{"ବ", "ଡ଼"}},
+		// NFC composes େ + ା into ୋ.
+		{"ଘେ\u0b3eର", []string{"ଘ", "ୋ", "ର"}},
+		{"hello ଘର", []string{"ଘ", "ର"}},
+		{"", nil},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.glyphs, Decompose(v.word), v.word)
+	}
+}
@@ -0,0 +1,74 @@
+package odiphone
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into words on whitespace and the danda (। and ॥)
+// that marks the end of a sentence, even when it is glued to a word.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(text, isBoundary)
+}
+
+// Splitter splits text into the tokens to encode, eg: with domain-specific
+// rules for hashtags, mentions, or URLs.
+type Splitter interface {
+	Split(text string) []string
+}
+
+// SplitterFunc is a function that is a Splitter.
+type SplitterFunc func(text string) []string
+
+// Split calls f(text).
+func (f SplitterFunc) Split(text string) []string {
+	return f(text)
+}
+
+// DefaultSplitter is the Splitter of Tokenize.
+var DefaultSplitter Splitter = SplitterFunc(Tokenize)
+
+// TokenizeWith splits text into tokens with the Splitter s, or with
+// DefaultSplitter if s is nil. Encode the tokens with EncodeMany.
+func TokenizeWith(text string, s Splitter) []string {
+	if s == nil {
+		s = DefaultSplitter
+	}
+	return s.Split(text)
+}
+
+func isBoundary(r rune) bool {
+	return unicode.IsSpace(r) || r == '।' || r == '॥'
+}
+
+// Span is a token of a text with its byte offsets in the text, such that
+// text[Start:End] == Text.
+type Span struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// TokenizeWithSpans is the same as Tokenize, but also returns the byte
+// offsets of every token in text, eg: to highlight matches in the source.
+func TokenizeWithSpans(text string) []Span {
+	var (
+		out   []Span
+		start = -1
+	)
+	for i, r := range text {
+		switch {
+		case isBoundary(r):
+			if start >= 0 {
+				out = append(out, Span{Text: text[start:i], Start: start, End: i})
+				start = -1
+			}
+		case start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		out = append(out, Span{Text: text[start:], Start: start, End: len(text)})
+	}
+	return out
+}
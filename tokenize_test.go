@@ -0,0 +1,69 @@
+package odiphone
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	require.Equal(t, []string{"ଶବ୍ଦ", "ଭ୍ରମର"}, Tokenize("ଶବ୍ଦ।ଭ୍ରମର"))
+	require.Equal(t, []string{"ଶବ୍ଦ", "ଭ୍ରମର", "ଭ୍ରମଣ"}, Tokenize("  ଶବ୍ଦ। ଭ୍ରମର\n\tଭ୍ରମଣ॥ "))
+	require.Empty(t, Tokenize(" ।  "))
+}
+
+func TestTokenizeWith(t *testing.T) {
+	const text = "ଭ୍ରମର, #ଓଡ଼ିଶା_ଦିବସ ଓଡ଼ିଶା_ଦିବସ।ଘର"
+	require.Equal(t, Tokenize(text), TokenizeWith(text, nil))
+	require.Equal(t, Tokenize(text), TokenizeWith(text, DefaultSplitter))
+
+	// A splitter that also splits on punctuation, but keeps hashtags intact.
+	hashtags := SplitterFunc(func(text string) []string {
+		var out []string
+		for _, f := range Tokenize(text) {
+			if strings.HasPrefix(f, "#") {
+				out = append(out, f)
+				continue
+			}
+			out = append(out, strings.FieldsFunc(f, unicode.IsPunct)...)
+		}
+		return out
+	})
+	words := TokenizeWith(text, hashtags)
+	require.Equal(t, []string{"ଭ୍ରମର", "#ଓଡ଼ିଶା_ଦିବସ", "ଓଡ଼ିଶା", "ଦିବସ", "ଘର"}, words)
+
+	// The encoder handles the phonetics of each token.
+	phone := New()
+	keys := phone.EncodeMany(words)
+	require.Equal(t, phone.EncodeKeys("ଭ୍ରମର"), keys[0])
+	require.Equal(t, phone.EncodeKeys("ଓଡ଼ିଶାଦିବସ"), keys[1])
+}
+
+func TestEncodeDanda(t *testing.T) {
+	phone := New()
+	require.Equal(t, phone.EncodeKeys("ଶବ୍ଦ"), phone.EncodeKeys("ଶବ୍ଦ।"))
+	require.Equal(t, phone.EncodeKeys("ଶବ୍ଦ"), phone.EncodeKeys("ଶବ୍ଦ॥"))
+}
+
+func TestTokenizeWithSpans(t *testing.T) {
+	const text = "  ଶବ୍ଦ। ଭ୍ରମର\n\tଭ୍ରମଣ॥ ab"
+	spans := TokenizeWithSpans(text)
+	require.Equal(t, []Span{
+		{"ଶବ୍ଦ", 2, 14},
+		{"ଭ୍ରମର", 18, 33},
+		{"ଭ୍ରମଣ", 35, 50},
+		{"ab", 54, 56},
+	}, spans)
+
+	var words []string
+	for _, s := range spans {
+		require.Equal(t, s.Text, text[s.Start:s.End])
+		words = append(words, s.Text)
+	}
+	require.Equal(t, Tokenize(text), words)
+
+	require.Empty(t, TokenizeWithSpans(" ।  "))
+	require.Equal(t, []Span{{"ଘର", 0, 6}}, TokenizeWithSpans("ଘର"))
+}
@@ -0,0 +1,160 @@
+package odiphone
+
+// translit is a transliteration scheme of Odia letters.
+type translit struct {
+	// letters is the transliteration of vowels and consonants.
+	letters map[rune]string
+
+	// flaps is the transliteration of consonants that change with a nukta.
+	flaps map[rune]string
+
+	// signs is the transliteration of vowel signs (matras).
+	signs map[rune]string
+
+	// schwa is the inherent vowel of a consonant without a vowel sign.
+	schwa string
+
+	anusvara string
+	visarga  string
+
+	// nasalize applies a chandrabindu to the preceding vowel.
+	nasalize func(string) string
+}
+
+// romanLetters is a readable Roman transliteration of vowels and consonants.
+var romanLetters = map[rune]string{
+	'ଅ': "a",
+	'ଆ': "aa",
+	'ଇ': "i",
+	'ଈ': "ee",
+	'ଉ': "u",
+	'ଊ': "oo",
+	'ଋ': "ru",
+	'ୠ': "roo",
+	'ଏ': "e",
+	'ଐ': "ai",
+	'ଓ': "o",
+	'ଔ': "au",
+
+	'କ': "k",
+	'ଖ': "kh",
+	'ଗ': "g",
+	'ଘ': "gh",
+	'ଙ': "ng",
+	'ଚ': "ch",
+	'ଛ': "chh",
+	'ଜ': "j",
+	'ଝ': "jh",
+	'ଞ': "ny",
+	'ଟ': "t",
+	'ଠ': "th",
+	'ଡ': "d",
+	'ଢ': "dh",
+	'ଣ': "n",
+	'ତ': "t",
+	'ଥ': "th",
+	'ଦ': "d",
+	'ଧ': "dh",
+	'ନ': "n",
+	'ପ': "p",
+	'ଫ': "ph",
+	'ବ': "b",
+	'ଭ': "bh",
+	'ମ': "m",
+	'ଯ': "j",
+	'ର': "r",
+	'ଲ': "l",
+	'ଳ': "l",
+	'ଵ': "v",
+	'ଶ': "sh",
+	'ଷ': "sh",
+	'ସ': "s",
+	'ହ': "h",
+	'ୟ': "y",
+	'ୱ': "w",
+	'ଡ଼': "r",
+	'ଢ଼': "rh",
+}
+
+var roman = translit{
+	letters: romanLetters,
+	flaps: map[rune]string{
+		'ଡ': "r",
+		'ଢ': "rh",
+	},
+	signs: map[rune]string{
+		'ା': "aa",
+		'ି': "i",
+		'ୀ': "ee",
+		'ୁ': "u",
+		'ୂ': "oo",
+		'ୃ': "ru",
+		'ୄ': "roo",
+		'େ': "e",
+		'ୈ': "ai",
+		'ୋ': "o",
+		'ୌ': "au",
+	},
+	schwa:    "a",
+	anusvara: "n",
+	visarga:  "h",
+	nasalize: func(s string) string { return s + "n" },
+}
+
+// transliterate returns the transliterated letters of a normalized input.
+// Every consonant is followed by its vowel sign, or the inherent vowel
+// unless it has a halant.
+func (t translit) transliterate(input string) []string {
+	var (
+		out []string
+		rs  = []rune(input)
+	)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+
+		p, ok := t.letters[r]
+		if !ok {
+			switch r {
+			case 'ଂ':
+				out = append(out, t.anusvara)
+			case 'ଃ':
+				out = append(out, t.visarga)
+			case 'ଁ':
+				if len(out) > 0 {
+					out[len(out)-1] = t.nasalize(out[len(out)-1])
+				}
+			}
+			continue
+		}
+
+		if _, isVowel := vowels[string(r)]; isVowel {
+			out = append(out, p)
+			continue
+		}
+
+		// Consonant.
+		if i+1 < len(rs) && rs[i+1] == nukta {
+			if f, ok := t.flaps[r]; ok {
+				p = f
+			}
+			i++
+		}
+		out = append(out, p)
+
+		// The vowel sign, inherent vowel, or none with a halant.
+		if i+1 < len(rs) {
+			if rs[i+1] == halant {
+				i++
+				continue
+			}
+			if s, ok := t.signs[rs[i+1]]; ok {
+				out = append(out, s)
+				i++
+				continue
+			}
+		}
+		out = append(out, t.schwa)
+	}
+
+	return out
+}
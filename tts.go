@@ -0,0 +1,188 @@
+package odiphone
+
+import "strings"
+
+// ttsLetters is the IPA of vowels and consonants.
+var ttsLetters = map[rune]string{
+	'ଅ': "ɔ",
+	'ଆ': "a",
+	'ଇ': "i",
+	'ଈ': "iː",
+	'ଉ': "u",
+	'ଊ': "uː",
+	'ଋ': "ru",
+	'ୠ': "ruː",
+	'ଏ': "e",
+	'ଐ': "ɔi",
+	'ଓ': "o",
+	'ଔ': "ɔu",
+
+	'କ': "k",
+	'ଖ': "kʰ",
+	'ଗ': "g",
+	'ଘ': "gʰ",
+	'ଙ': "ŋ",
+	'ଚ': "tʃ",
+	'ଛ': "tʃʰ",
+	'ଜ': "dʒ",
+	'ଝ': "dʒʰ",
+	'ଞ': "ɲ",
+	'ଟ': "ʈ",
+	'ଠ': "ʈʰ",
+	'ଡ': "ɖ",
+	'ଢ': "ɖʰ",
+	'ଣ': "ɳ",
+	'ତ': "t",
+	'ଥ': "tʰ",
+	'ଦ': "d",
+	'ଧ': "dʰ",
+	'ନ': "n",
+	'ପ': "p",
+	'ଫ': "pʰ",
+	'ବ': "b",
+	'ଭ': "bʰ",
+	'ମ': "m",
+	'ଯ': "dʒ",
+	'ର': "r",
+	'ଲ': "l",
+	'ଳ': "ɭ",
+	'ଵ': "w",
+	'ଶ': "s",
+	'ଷ': "s",
+	'ସ': "s",
+	'ହ': "h",
+	'ୟ': "j",
+	'ୱ': "w",
+	'ଡ଼': "ɽ",
+	'ଢ଼': "ɽʰ",
+}
+
+// tts is the IPA transliteration. The inherent vowel is retained in Odia
+// pronunciation, including at the end of a word.
+var tts = translit{
+	letters: ttsLetters,
+	flaps: map[rune]string{
+		'ଡ': "ɽ",
+		'ଢ': "ɽʰ",
+	},
+	signs:    ttsSigns,
+	schwa:    "ɔ",
+	anusvara: "ŋ",
+	visarga:  "h",
+	nasalize: func(s string) string { return s + "\u0303" },
+}
+
+// ttsSigns is the IPA of vowel signs (matras).
+var ttsSigns = map[rune]string{
+	'ା': "a",
+	'ି': "i",
+	'ୀ': "iː",
+	'ୁ': "u",
+	'ୂ': "uː",
+	'ୃ': "ru",
+	'ୄ': "ruː",
+	'େ': "e",
+	'ୈ': "ɔi",
+	'ୋ': "o",
+	'ୌ': "ɔu",
+}
+
+// PhoneticForTTS returns a space separated IPA phoneme sequence of a word
+// for text-to-speech tools, eg: ଭ୍ରମର = "bʰ r ɔ m ɔ r ɔ". Unlike the keys,
+// it retains vowel length (ː) and the inherent vowel (ɔ).
+func (od *ODIphone) PhoneticForTTS(word string) string {
+	return strings.Join(tts.transliterate(hConjuncts.Replace(od.normalize(word))), " ")
+}
+
+// arpabetLetters is the ARPAbet-like notation of vowels and consonants. The
+// sounds that English has are the ARPAbet symbols, and the others are
+// extended from them: an H suffix for aspiration (eg: ଖ = KH, and ଥ = TH, as
+// Odia has no dental fricatives), doubled letters for the retroflexes (eg:
+// ଟ = TT), and RR for the flap. Letters of two sounds are space separated.
+var arpabetLetters = map[rune]string{
+	'ଅ': "AO",
+	'ଆ': "AA",
+	'ଇ': "IH",
+	'ଈ': "IY",
+	'ଉ': "UH",
+	'ଊ': "UW",
+	'ଋ': "R UH",
+	'ୠ': "R UW",
+	'ଏ': "EY",
+	'ଐ': "OY",
+	'ଓ': "OW",
+	'ଔ': "AW",
+
+	'କ': "K",
+	'ଖ': "KH",
+	'ଗ': "G",
+	'ଘ': "GH",
+	'ଙ': "NG",
+	'ଚ': "CH",
+	'ଛ': "CHH",
+	'ଜ': "JH",
+	'ଝ': "JHH",
+	'ଞ': "NY",
+	'ଟ': "TT",
+	'ଠ': "TTH",
+	'ଡ': "DD",
+	'ଢ': "DDH",
+	'ଣ': "NN",
+	'ତ': "T",
+	'ଥ': "TH",
+	'ଦ': "D",
+	'ଧ': "DH",
+	'ନ': "N",
+	'ପ': "P",
+	'ଫ': "PH",
+	'ବ': "B",
+	'ଭ': "BH",
+	'ମ': "M",
+	'ଯ': "JH",
+	'ର': "R",
+	'ଲ': "L",
+	'ଳ': "LL",
+	'ଵ': "W",
+	'ଶ': "S",
+	'ଷ': "S",
+	'ସ': "S",
+	'ହ': "HH",
+	'ୟ': "Y",
+	'ୱ': "W",
+	'ଡ଼': "RR",
+	'ଢ଼': "RRH",
+}
+
+// arpabet is the ARPAbet-like transliteration. A nasalized vowel is the
+// vowel and N, as ARPAbet has no nasal vowels.
+var arpabet = translit{
+	letters: arpabetLetters,
+	flaps: map[rune]string{
+		'ଡ': "RR",
+		'ଢ': "RRH",
+	},
+	signs: map[rune]string{
+		'ା': "AA",
+		'ି': "IH",
+		'ୀ': "IY",
+		'ୁ': "UH",
+		'ୂ': "UW",
+		'ୃ': "R UH",
+		'ୄ': "R UW",
+		'େ': "EY",
+		'ୈ': "OY",
+		'ୋ': "OW",
+		'ୌ': "AW",
+	},
+	schwa:    "AO",
+	anusvara: "NG",
+	visarga:  "HH",
+	nasalize: func(s string) string { return s + " N" },
+}
+
+// ARPAbet returns the phoneme sequence of a word in an ARPAbet-like notation
+// for speech tools, eg: ଭ୍ରମର = [BH R AO M AO R AO]. Like PhoneticForTTS,
+// and unlike the keys, it retains vowel length and the inherent vowel.
+func (od *ODIphone) ARPAbet(word string) []string {
+	return strings.Fields(strings.Join(arpabet.transliterate(hConjuncts.Replace(od.normalize(word))), " "))
+}
@@ -0,0 +1,63 @@
+package odiphone
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneticForTTS(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		word, tts string
+	}{
+		{"ଭ୍ରମର", "bʰ r ɔ m ɔ r ɔ"},
+		{"ଘର", "gʰ ɔ r ɔ"},
+		{"ଘରେ", "gʰ ɔ r e"},
+		// Long and short vowels.
+		{"ଦୀପ", "d iː p ɔ"},
+		{"ଦିନ", "d i n ɔ"},
+		{"ଊଷା", "uː s a"},
+		{"ଅଂଶ", "ɔ ŋ s ɔ"},
+		{"ଚାଁଦ", "tʃ a\u0303 d ɔ"},
+		{"ବଡ଼", "b ɔ ɽ ɔ"},
+		{"ବ\u0b5c", "b ɔ ɽ ɔ"}, // Precomposed ଡ଼.
+		{"ବ୍ରାହ୍ମଣ", "b r a m h ɔ ɳ ɔ"},
+		{"", ""},
+	}
+	for _, v := range tests {
+		require.Equal(t, v.tts, phone.PhoneticForTTS(v.word), v.word)
+	}
+}
+
+func TestARPAbet(t *testing.T) {
+	phone := New()
+	tests := []struct {
+		word    string
+		arpabet string
+	}{
+		{"ଭ୍ରମର", "BH R AO M AO R AO"},
+		{"ଘରେ", "GH AO R EY"},
+		// Long and short vowels.
+		{"ଦୀପ", "D IY P AO"},
+		{"ଦିନ", "D IH N AO"},
+		{"ଊଷା", "UW S AA"},
+		// Retroflexes and the flap.
+		{"ଟଣା", "TT AO NN AA"},
+		{"ବଡ଼", "B AO RR AO"},
+		// Letters of two sounds.
+		{"କୃଷି", "K R UH S IH"},
+		{"ଋଷି", "R UH S IH"},
+		// Modifiers.
+		{"ଅଂଶ", "AO NG S AO"},
+		{"ଦୁଃଖ", "D UH HH KH AO"},
+		{"ଚାଁଦ", "CH AA N D AO"},
+		{"ବ୍ରାହ୍ମଣ", "B R AA M HH AO NN AO"},
+	}
+	for _, v := range tests {
+		require.Equal(t, strings.Fields(v.arpabet), phone.ARPAbet(v.word), v.word)
+	}
+	require.Empty(t, phone.ARPAbet(""))
+	require.Empty(t, phone.ARPAbet("hello"))
+}
@@ -0,0 +1,10 @@
+package odiphone
+
+// Version is the version of the package.
+const Version = "0.1.0"
+
+// AlgorithmVersion is the version of the phonetic tables and rules. It is
+// bumped whenever a change in them changes the keys generated for a word.
+// Store it alongside persisted keys to detect when they need to be
+// regenerated.
+const AlgorithmVersion = 17
@@ -0,0 +1,56 @@
+package odiphone
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fingerprintWords are encoded into the algorithm fingerprint so that rule
+// changes, and not just table changes, are detected.
+var fingerprintWords = []string{
+	"ଅଂଶ", "ଭ୍ରମର", "ଭ୍ରମରେ", "ଭ୍ରମଣ", "ଐରାବତ", "ଲକ୍ଷ୍ୟ", "ରକ୍ଷା", "ବିଦ୍ୟା",
+	"ସତ୍ୟ", "ବହିମାନେ", "ଶଙ୍କର", "ଗଙ୍ଗା", "ଭକ୍ତ", "ଅଞ୍ଜଳି", "ଘର", "କୃଷ୍ଣ",
+	"ମନ୍ତ୍ର", "ଅସ୍ତ୍ର", "ସ୍ୱର", "ବ୍ରାହ୍ମଣ", "ନୂଆ", "ଦିଆ", "ଉଆସ", "ବ\u0b5c", "ଘ\u0b47\u0b3eଡ\u0b3c\u0b3e",
+	"ଅାମ", "ବ\u0902ଶ", "ପ\u0b5dା", "ଆକାଙ୍କ୍ଷା", "ଅନ୍ନ", "ଲକ\u0b55୍ଷ", "ଶ୍ରୀ", "ହ୍ରଦ",
+}
+
+// The fingerprint of the tables and rules at AlgorithmVersion. If this test
+// fails, the keys have changed: bump AlgorithmVersion and update both values.
+const (
+	fingerprintVersion = 17
+	fingerprint        = "7417fcf4d2acd98665fa1f70c992ef3884e337b5534b3c8bd981fe529bfc55bf"
+)
+
+func algorithmFingerprint() string {
+	var (
+		h   = sha256.New()
+		all []string
+	)
+	for _, m := range []map[string]string{vowels, consonants, compounds, modifiers} {
+		for k, v := range m {
+			all = append(all, k+"="+v)
+		}
+	}
+	sort.Strings(all)
+	for _, s := range all {
+		fmt.Fprintln(h, s)
+	}
+
+	phone := New()
+	for _, w := range fingerprintWords {
+		fmt.Fprintln(h, w, phone.EncodeKeys(w))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+func TestVersion(t *testing.T) {
+	require.NotEmpty(t, Version)
+	require.Greater(t, AlgorithmVersion, 0)
+	require.Equal(t, fingerprintVersion, AlgorithmVersion)
+	require.Equal(t, fingerprint, algorithmFingerprint(),
+		"keys have changed: bump AlgorithmVersion and update the fingerprint")
+}